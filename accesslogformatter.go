@@ -0,0 +1,113 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Params read by the AccessLogFormatter
+const (
+	AccessRemoteAddrKey = "remote_addr"
+	AccessUserKey       = "user"
+	AccessMethodKey     = "method"
+	AccessPathKey       = "path"
+	AccessProtocolKey   = "protocol"
+	AccessStatusKey     = "status"
+	AccessBytesKey      = "bytes"
+	AccessRefererKey    = "referer"
+	AccessUserAgentKey  = "user_agent"
+	AccessLatencyKey    = "latency"
+)
+
+// access log constants
+const (
+	accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+	accessLogNil        = "-"
+)
+
+// AccessLogFormatter formats entries carrying the access params, such as
+// remote_addr, method, path and status, as Apache Common Log Format lines,
+// or Combined Log Format with the referer and user agent. Missing values are
+// written as a dash. Other params and the message are not written.
+type AccessLogFormatter struct {
+	// Combined adds the referer and user agent
+	Combined bool
+
+	// Latency adds the request latency in microseconds at the end of the
+	// line, like the %D directive of apache
+	Latency bool
+}
+
+func (f *AccessLogFormatter) Format(entry *Entry) ([]byte, error) {
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	bytesSent := accessLogParam(entry, AccessBytesKey)
+	if bytesSent == "0" {
+		bytesSent = accessLogNil
+	}
+	_, _ = fmt.Fprintf(buffer, `%s - %s [%s] "%s %s %s" %s %s`,
+		accessLogParam(entry, AccessRemoteAddrKey),
+		accessLogParam(entry, AccessUserKey),
+		entry.Time.Format(accessLogTimeFormat),
+		accessLogEscape(accessLogParam(entry, AccessMethodKey)),
+		accessLogEscape(accessLogParam(entry, AccessPathKey)),
+		accessLogEscape(accessLogParam(entry, AccessProtocolKey)),
+		accessLogParam(entry, AccessStatusKey),
+		bytesSent,
+	)
+	if f.Combined {
+		_, _ = fmt.Fprintf(buffer, ` "%s" "%s"`,
+			accessLogEscape(accessLogParam(entry, AccessRefererKey)),
+			accessLogEscape(accessLogParam(entry, AccessUserAgentKey)),
+		)
+	}
+	if f.Latency {
+		buffer.WriteByte(' ')
+		switch latency := entry.Data[AccessLatencyKey].(type) {
+		case time.Duration:
+			_, _ = fmt.Fprint(buffer, int64(latency/time.Microsecond))
+		default:
+			buffer.WriteString(accessLogParam(entry, AccessLatencyKey))
+		}
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// accessLogParam returns the param as a string, or a dash if it is missing
+func accessLogParam(entry *Entry, key string) string {
+	v, ok := entry.Data[key]
+	if !ok || v == nil {
+		return accessLogNil
+	}
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+	if s == "" {
+		return accessLogNil
+	}
+	return s
+}
+
+// accessLogEscape escapes quotes, backslashes and non printable characters
+// the way apache does in quoted fields
+func accessLogEscape(s string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c >= 0x7f:
+			_, _ = fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package rogger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// time the queued entries are given to be written before exiting on fatal
+const exitFlushTimeout = 5 * time.Second
+
+// asyncWrite is a formatted entry queued to be written, with the settings
+// of the logger when it was queued, or a flush waiting for the entries
+// queued before it
+type asyncWrite struct {
+	write    pendingWrite
+	settings backgroundSettings
+	flush    chan struct{}
+}
+
+// asyncQueue writes the entries in the background when the logger is
+// asynchronous
+type asyncQueue struct {
+	mu      sync.Mutex
+	queue   chan asyncWrite
+	stopped chan struct{}
+	drained chan struct{}
+	closed  bool
+	dropped uint64
+
+	// whether the queue reached the high water mark, until it is drained
+	aboveHighWater bool
+}
+
+// SetAsync makes the logger write asynchronously with a queue of the size,
+// see AsyncQueueSize
+func (logger *Logger) SetAsync(queueSize int) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.AsyncQueueSize = queueSize
+}
+
+// Flush waits for the entries queued by asynchronous writes to be written,
+// or for the context to be done. It returns right away when the logger is
+// not asynchronous, or closed.
+func (logger *Logger) Flush(ctx context.Context) error {
+	q := &logger.async
+	q.mu.Lock()
+	queue, stopped, drained, closed := q.queue, q.stopped, q.drained, q.closed
+	q.mu.Unlock()
+	if queue == nil || closed {
+		return nil
+	}
+	done := make(chan struct{})
+	select {
+	case queue <- asyncWrite{flush: done}:
+	case <-stopped:
+		// closed in the meantime, which flushed the queue
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-drained:
+		// the queue was written and the writes stopped
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the queued entries and stops writing asynchronously, the
+// entries logged afterwards are written synchronously. It returns once the
+// entries queued in the meantime are written too. Closing it again does
+// nothing.
+func (logger *Logger) Close() error {
+	q := &logger.async
+	q.mu.Lock()
+	closed := q.closed
+	q.mu.Unlock()
+	if closed {
+		return nil
+	}
+	err := logger.Flush(context.Background())
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	drained := q.drained
+	if q.stopped != nil {
+		close(q.stopped)
+	}
+	q.mu.Unlock()
+	if drained != nil {
+		<-drained
+	}
+	return err
+}
+
+// writeAsync queues the entry to be written in the background, and reports
+// whether it did, which it does not once the logger is closed
+func (logger *Logger) writeAsync(out io.Writer, entry *Entry, p []byte) bool {
+	q := &logger.async
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	if q.queue == nil {
+		q.queue = make(chan asyncWrite, logger.AsyncQueueSize)
+		q.stopped = make(chan struct{})
+		q.drained = make(chan struct{})
+		go logger.drainAsync(q.queue, q.stopped, q.drained)
+	}
+	w := pendingWrite{out: out, level: entry.Level, p: append([]byte(nil), p...)}
+	var dropped, reached bool
+	select {
+	case q.queue <- asyncWrite{write: w, settings: logger.backgroundSettings()}:
+		reached = q.reachedHighWater(len(q.queue), cap(q.queue), logger.QueueHighWater)
+	default:
+		q.dropped++
+		dropped = true
+	}
+	depth := len(q.queue)
+	q.mu.Unlock()
+
+	if reached && logger.OnQueueHighWater != nil {
+		logger.OnQueueHighWater(depth)
+	}
+	if dropped {
+		logger.handleError(ErrorQueueFull, ErrQueueFull)
+		if logger.OnDrop != nil {
+			logger.OnDrop(entry, DropQueueFull)
+		}
+	}
+	return true
+}
+
+// reachedHighWater checks whether the queue just reached the mark, three
+// quarters of its capacity by default, it must be called with the lock held
+func (q *asyncQueue) reachedHighWater(depth, capacity, mark int) bool {
+	if mark <= 0 {
+		mark = capacity * 3 / 4
+	}
+	if depth < mark {
+		q.aboveHighWater = false
+		return false
+	}
+	if q.aboveHighWater {
+		return false
+	}
+	q.aboveHighWater = true
+	return true
+}
+
+// drainAsync writes the queued entries in order until the logger is closed,
+// and then the ones left
+func (logger *Logger) drainAsync(queue chan asyncWrite, stopped, drained chan struct{}) {
+	defer close(drained)
+	for {
+		select {
+		case w := <-queue:
+			logger.writeQueued(w, queue)
+		case <-stopped:
+			for {
+				select {
+				case w := <-queue:
+					logger.writeQueued(w, queue)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeQueued writes the queued entry, or completes the flush
+func (logger *Logger) writeQueued(w asyncWrite, queue chan asyncWrite) {
+	if w.flush != nil {
+		close(w.flush)
+		return
+	}
+	if _, err := writeLevel(w.write.out, w.write.level, w.write.p); err != nil {
+		logger.reportError(w.settings.errorHandler, ErrorWrite, fmt.Errorf(w.settings.catalog.translate(CatalogWriteFailed), err))
+	}
+	q := &logger.async
+	q.mu.Lock()
+	q.reachedHighWater(len(queue), cap(queue), w.settings.highWater)
+	q.mu.Unlock()
+}
+
+// depth returns the number of entries waiting to be written
+func (q *asyncQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
+
+// droppedWrites returns the number of entries dropped as the queue was full
+func (q *asyncQueue) droppedWrites() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
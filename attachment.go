@@ -0,0 +1,96 @@
+package rogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Attachment replaces a param value written to the attachments of the
+// logger, referencing it by its ID
+type Attachment struct {
+	ID   string `json:"attachment_id"`
+	Size int    `json:"size"`
+}
+
+func (a Attachment) String() string {
+	return "attachment " + a.ID + " (" + strconv.Itoa(a.Size) + " bytes)"
+}
+
+// AttachmentSink stores the param values too big for the log, see
+// Logger.Attachments. It must be safe for concurrent use.
+type AttachmentSink interface {
+	Attach(id string, value []byte) error
+}
+
+// DirAttachments stores the attachments as files in the directory, named by
+// their ID
+type DirAttachments struct {
+	Dir string
+}
+
+func (d DirAttachments) Attach(id string, value []byte) error {
+	if err := os.MkdirAll(d.Dir, logDirPerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(d.Dir, id), value, logFilePerm)
+}
+
+// sequence of the attachment IDs, which are unique within the process
+var attachmentSequence uint64
+
+// SetAttachments sets the sink the param values longer than the threshold
+// are written to
+func (logger *Logger) SetAttachments(threshold int, sink AttachmentSink) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.AttachmentThreshold = threshold
+	logger.Attachments = sink
+}
+
+// attach writes the string and byte values longer than the threshold to the
+// attachments, replacing them in a copy of the params. The values which fail
+// to be written are kept. The replaced values are recorded in the sources.
+func (logger *Logger) attach(data Params, sources map[string]string) Params {
+	if logger.Attachments == nil || logger.AttachmentThreshold <= 0 {
+		return data
+	}
+	var attached Params
+	for k, v := range data {
+		var value []byte
+		switch v := v.(type) {
+		case string:
+			if len(v) > logger.AttachmentThreshold {
+				value = []byte(v)
+			}
+		case []byte:
+			if len(v) > logger.AttachmentThreshold {
+				value = v
+			}
+		}
+		if value == nil {
+			continue
+		}
+		id := fmt.Sprintf("%d-%d-%d", time.Now().UnixNano(), os.Getpid(), atomic.AddUint64(&attachmentSequence, 1))
+		if err := logger.Attachments.Attach(id, value); err != nil {
+			logger.handleError(ErrorAttachment, fmt.Errorf(logger.Catalog.translate(CatalogAttachmentFailed), err))
+			continue
+		}
+		if attached == nil {
+			attached = make(Params, len(data))
+			for dk, dv := range data {
+				attached[dk] = dv
+			}
+		}
+		attached[k] = Attachment{ID: id, Size: len(value)}
+		overrideSource(sources, k)
+	}
+	if attached == nil {
+		return data
+	}
+	return attached
+}
@@ -0,0 +1,127 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// BatchWriter is an optional interface for outputs which can deliver many
+// entries at once more efficiently, such as network sinks. When the logger
+// output implements it, LogBatch calls WriteBatch with the formatted entries
+// instead of Write.
+type BatchWriter interface {
+	io.Writer
+	WriteBatch(entries [][]byte) error
+}
+
+// LogBatch formats and writes the entries at once, for jobs importing or
+// migrating many related entries. The entries are prepared as if they were
+// logged one by one, at their own level and time, which is the current time
+// when it is not set, and the ones below the logger level are skipped. Only
+// their writes are grouped, under a single lock and as a single write per
+// output. Entries written to another output than the logger one, or routed
+// by their level, are written to it in their own batch. When the logger is
+// asynchronous or has a write timeout, and for outputs taking the entries
+// themselves such as a MultiOutput, the entries are written one by one as
+// they are otherwise.
+func (logger *Logger) LogBatch(entries []*Entry) {
+	prepared := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		entry := *e
+		entry.Logger = logger
+		if !entry.isLevelEnabled(entry.Level) || !entry.prepare(entry.Level, staticMessage(entry.Message)) {
+			continue
+		}
+		// the caller of the batch is not the one of its entries
+		entry.Caller, entry.callerPending = e.Caller, false
+		prepared = append(prepared, &entry)
+	}
+
+	logger.mu.lock()
+	start := time.Now()
+
+	// the formatted entries by output, in the order of their first entry
+	var outputs []io.Writer
+	batches := make(map[io.Writer][][]byte)
+	levels := make(map[io.Writer][]Level)
+	written := 0
+	for _, entry := range prepared {
+		buffer := bufferPool.Get().(*bytes.Buffer)
+		buffer.Reset()
+		entry.Buffer = buffer
+		formatted, err := logger.Formatter.Format(entry)
+		if err != nil {
+			entry.Buffer = nil
+			bufferPool.Put(buffer)
+			logger.handleError(ErrorFormat, fmt.Errorf(logger.Catalog.translate(CatalogFormatFailed), err))
+			continue
+		}
+		routed := entry.routedOutputs()
+		if len(routed) == 0 {
+			routed = []io.Writer{entry.output()}
+		}
+		for _, out := range routed {
+			if logger.writesEntries(out) {
+				entry.writeTo(out, formatted)
+				continue
+			}
+			if _, ok := batches[out]; !ok {
+				outputs = append(outputs, out)
+			}
+			// the formatted entry is copied as the buffer is reused
+			batches[out] = append(batches[out], append([]byte(nil), formatted...))
+			levels[out] = append(levels[out], entry.Level)
+		}
+		entry.Buffer = nil
+		bufferPool.Put(buffer)
+		written++
+	}
+
+	for _, out := range outputs {
+		if err := logger.writeBatch(out, levels[out], batches[out]); err != nil {
+			logger.handleError(ErrorWrite, fmt.Errorf(logger.Catalog.translate(CatalogWriteFailed), err))
+		}
+	}
+
+	// the latency is spread over the entries so that they are all counted
+	if written > 0 {
+		latency := time.Since(start) / time.Duration(written)
+		for i := 0; i < written; i++ {
+			logger.writeLatency.observe(latency)
+		}
+	}
+	logger.mu.unlock()
+
+	for _, entry := range prepared {
+		entry.written()
+	}
+}
+
+// writesEntries checks whether the entries are written to the output one by
+// one, as they are queued, written with a timeout or taken by the output
+func (logger *Logger) writesEntries(out io.Writer) bool {
+	if _, ok := out.(entryWriter); ok {
+		return true
+	}
+	return logger.AsyncQueueSize > 0 || logger.WriteTimeout > 0
+}
+
+// writeBatch writes the formatted entries to the output, as a batch when it
+// supports it, one by one when it needs their level, and joined otherwise
+func (logger *Logger) writeBatch(out io.Writer, levels []Level, batch [][]byte) error {
+	if w, ok := out.(BatchWriter); ok {
+		return w.WriteBatch(batch)
+	}
+	if _, ok := out.(LevelWriter); ok {
+		for i, p := range batch {
+			if _, err := writeLevel(out, levels[i], p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := out.Write(bytes.Join(batch, nil))
+	return err
+}
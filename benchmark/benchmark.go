@@ -0,0 +1,205 @@
+// Package benchmark generates representative entries and measures the
+// throughput and allocations of formatters against them, so that formatter
+// authors can track regressions.
+//
+//	corpus := benchmark.Corpus(benchmark.CorpusOptions{ReportCaller: true})
+//	for _, result := range benchmark.MeasureBuiltins(corpus) {
+//		fmt.Println(result)
+//	}
+package benchmark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// corpus defaults
+const (
+	defaultCorpusSize = 100
+	defaultMaxParams  = 10
+)
+
+// CorpusOptions controls the generated entries
+type CorpusOptions struct {
+	// Size is the number of entries, 100 by default
+	Size int
+
+	// MaxParams is the maximum number of params of an entry, the number
+	// varies between zero and it, 10 by default
+	MaxParams int
+
+	// ReportCaller adds a caller to the entries
+	ReportCaller bool
+
+	// Seed of the generator, the same seed gives the same corpus
+	Seed int64
+}
+
+// Corpus generates entries with a mix of levels, messages, events and
+// param value types, such as strings, numbers, booleans, times, durations,
+// errors and nested maps
+func Corpus(opts CorpusOptions) []*rogger.Entry {
+	if opts.Size <= 0 {
+		opts.Size = defaultCorpusSize
+	}
+	if opts.MaxParams <= 0 {
+		opts.MaxParams = defaultMaxParams
+	}
+	random := rand.New(rand.NewSource(opts.Seed))
+	logger := rogger.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.SetReportCaller(opts.ReportCaller)
+	base := time.Date(2020, time.January, 2, 15, 4, 5, 0, time.UTC)
+	entries := make([]*rogger.Entry, opts.Size)
+	for i := range entries {
+		entry := rogger.NewEntry(logger)
+		entry.Time = base.Add(time.Duration(i) * time.Millisecond)
+		entry.Level = rogger.Level(random.Intn(int(rogger.FatalLevel) + 1))
+		if random.Intn(10) == 0 {
+			entry.EventName = fmt.Sprintf("event_%d", random.Intn(5))
+		} else {
+			entry.Message = messages[random.Intn(len(messages))]
+		}
+		for p := random.Intn(opts.MaxParams + 1); p > 0; p-- {
+			entry.Data[fmt.Sprintf("param_%d", random.Intn(opts.MaxParams*2))] = paramValue(random)
+		}
+		if opts.ReportCaller {
+			entry.Caller = &runtime.Frame{
+				Function: "github.com/example/service/handlers.(*Handler).ServeHTTP",
+				File:     "/src/github.com/example/service/handlers/handler.go",
+				Line:     10 + random.Intn(500),
+			}
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+var messages = []string{
+	"request served",
+	"cache miss",
+	"retrying connection to upstream",
+	"user \"admin\" logged in",
+	"multi line\nmessage with a stack\n\tat main.go:10",
+	"",
+}
+
+func paramValue(random *rand.Rand) interface{} {
+	switch random.Intn(9) {
+	case 0:
+		return "value"
+	case 1:
+		return "a longer value with spaces, quotes \" and = signs"
+	case 2:
+		return random.Int63()
+	case 3:
+		return random.Float64() * 1000
+	case 4:
+		return random.Intn(2) == 0
+	case 5:
+		return time.Date(2020, time.January, 2, 15, 4, 5, random.Intn(1e9), time.UTC)
+	case 6:
+		return time.Duration(random.Int63n(int64(time.Second)))
+	case 7:
+		return errors.New("connection refused")
+	}
+	return map[string]interface{}{"id": random.Intn(100), "name": "nested"}
+}
+
+// Result of measuring a formatter
+type Result struct {
+	Name string
+
+	// time, allocations and allocated bytes per formatted entry
+	NsPerEntry     int64
+	AllocsPerEntry int64
+	BytesPerEntry  int64
+
+	// average size of the formatted entries
+	OutputBytesPerEntry int64
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%-12s %8d ns/entry %6d B/entry %4d allocs/entry %6d output B/entry",
+		r.Name, r.NsPerEntry, r.BytesPerEntry, r.AllocsPerEntry, r.OutputBytesPerEntry)
+}
+
+// Measure formats the corpus repeatedly with the formatter, reusing a
+// buffer the way the logger does
+func Measure(name string, formatter rogger.Formatter, corpus []*rogger.Entry) Result {
+	var output int64
+	buffer := new(bytes.Buffer)
+	result := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		output = 0
+		for i := 0; i < b.N; i++ {
+			entry := corpus[i%len(corpus)]
+			buffer.Reset()
+			entry.Buffer = buffer
+			formatted, err := formatter.Format(entry)
+			if err != nil {
+				b.Fatal(err)
+			}
+			output += int64(len(formatted))
+		}
+		for _, entry := range corpus {
+			entry.Buffer = nil
+		}
+	})
+	r := Result{
+		Name:           name,
+		NsPerEntry:     result.NsPerOp(),
+		AllocsPerEntry: result.AllocsPerOp(),
+		BytesPerEntry:  result.AllocedBytesPerOp(),
+	}
+	if result.N > 0 {
+		r.OutputBytesPerEntry = output / int64(result.N)
+	}
+	return r
+}
+
+// Builtins returns the built in formatters by name, with their default
+// options
+func Builtins() map[string]rogger.Formatter {
+	formatters := map[string]rogger.Formatter{
+		"text":      &rogger.TextFormatter{DisableColors: true},
+		"logfmt":    &rogger.LogfmtFormatter{},
+		"dev":       &rogger.DevFormatter{DisableColors: true},
+		"syslog":    &rogger.SyslogFormatter{Hostname: "benchmark"},
+		"cef":       &rogger.CEFFormatter{Vendor: "rogger", Product: "benchmark", Version: "1"},
+		"msgpack":   &rogger.MsgpackFormatter{},
+		"cbor":      &rogger.CBORFormatter{},
+		"accesslog": &rogger.AccessLogFormatter{Combined: true},
+		"glog":      &rogger.GlogFormatter{},
+		"journal":   &rogger.JournalFormatter{},
+		"csv":       &rogger.CSVFormatter{Columns: []string{"time", "level", "message", "param_0", "param_1"}},
+		"nop":       rogger.NopFormatter{},
+	}
+	addJSONBuiltins(formatters)
+	return formatters
+}
+
+// MeasureBuiltins measures every built in formatter against the corpus,
+// sorted by name
+func MeasureBuiltins(corpus []*rogger.Entry) []Result {
+	formatters := Builtins()
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]Result, 0, len(names))
+	for _, name := range names {
+		results = append(results, Measure(name, formatters[name], corpus))
+	}
+	return results
+}
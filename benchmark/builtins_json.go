@@ -0,0 +1,15 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package benchmark
+
+import "github.com/sinhashubham95/rogger"
+
+// addJSONBuiltins adds the json based formatters, which the lite build
+// leaves out
+func addJSONBuiltins(formatters map[string]rogger.Formatter) {
+	formatters["json"] = &rogger.JSONFormatter{}
+	formatters["gelf"] = &rogger.GELFFormatter{Host: "benchmark"}
+	formatters["cloud"] = &rogger.CloudLoggingFormatter{}
+	formatters["otlp"] = &rogger.OTLPFormatter{}
+}
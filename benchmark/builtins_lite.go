@@ -0,0 +1,10 @@
+//go:build roggerlite
+// +build roggerlite
+
+package benchmark
+
+import "github.com/sinhashubham95/rogger"
+
+// addJSONBuiltins adds nothing, the lite build leaves out the json based
+// formatters
+func addJSONBuiltins(formatters map[string]rogger.Formatter) {}
@@ -0,0 +1,220 @@
+// Package bridge ingests the json lines written by other loggers, such as
+// logrus, zap and slog, and logs them again through a rogger logger, for
+// wrapping sidecar processes and subprocesses.
+//
+//	cmd := exec.Command("sidecar")
+//	stdout, _ := cmd.StdoutPipe()
+//	_ = cmd.Start()
+//	b := &bridge.Bridge{Logger: logger}
+//	go b.Ingest(stdout)
+//
+// The time, level, message, error and caller keys of these loggers are
+// mapped to the fields of the entries, and the other keys are kept as
+// params.
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// maxLineSize is the size of the longest line that can be ingested
+const maxLineSize = 1 << 20
+
+// keys used by the supported loggers, in order of preference
+var (
+	timeKeys    = []string{"time", "ts", "timestamp"}
+	levelKeys   = []string{"level", "severity"}
+	messageKeys = []string{"msg", "message"}
+	errorKeys   = []string{"error", "err"}
+)
+
+// the layouts of the string times, the zap one has no colon in the zone
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+}
+
+// Bridge maps json lines to entries of a logger
+type Bridge struct {
+	// Logger the entries are logged through
+	Logger *rogger.Logger
+
+	// DefaultLevel of the lines with no known level, and of the lines which
+	// are not json. The zero value, DebugLevel, is taken as info.
+	DefaultLevel rogger.Level
+
+	// DropInvalid drops the lines which are not json objects, instead of
+	// logging them as messages
+	DropInvalid bool
+}
+
+// Ingest logs the lines of the reader until its end
+func (b *Bridge) Ingest(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := b.Entry(line)
+		if err != nil {
+			if b.DropInvalid {
+				continue
+			}
+			entry = b.newEntry()
+			entry.Message = string(line)
+		}
+		b.Logger.Replay(entry)
+	}
+	return scanner.Err()
+}
+
+// Entry maps a json line to an entry of the logger
+func (b *Bridge) Entry(line []byte) (*rogger.Entry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("not a json object, %v", err)
+	}
+	entry := b.newEntry()
+	if v, ok := take(fields, timeKeys); ok {
+		entry.Time = parseTime(v)
+	}
+	if v, ok := take(fields, levelKeys); ok {
+		if level, ok := parseLevel(fmt.Sprint(v)); ok {
+			entry.Level = level
+		}
+	}
+	if v, ok := take(fields, messageKeys); ok {
+		entry.Message = fmt.Sprint(v)
+	}
+	if v, ok := take(fields, errorKeys); ok {
+		fields["error"] = fmt.Sprint(v)
+	}
+	entry.Caller = takeCaller(fields)
+	entry.Data = fields
+	return entry, nil
+}
+
+func (b *Bridge) newEntry() *rogger.Entry {
+	entry := rogger.NewEntry(b.Logger)
+	entry.Level = b.DefaultLevel
+	if entry.Level == rogger.DebugLevel {
+		entry.Level = rogger.InfoLevel
+	}
+	entry.Time = time.Now()
+	return entry
+}
+
+// take removes the first of the keys present from the fields
+func take(fields map[string]interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		if v, ok := fields[key]; ok {
+			delete(fields, key)
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// parseTime reads string times, and numbers of seconds, milliseconds,
+// microseconds or nanoseconds since the epoch as guessed from their size
+func parseTime(v interface{}) time.Time {
+	switch v := v.(type) {
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			break
+		}
+		switch abs := math.Abs(f); {
+		case abs < 1e11:
+			sec, frac := math.Modf(f)
+			return time.Unix(int64(sec), int64(frac*1e9))
+		case abs < 1e14:
+			return time.Unix(0, int64(f*1e6))
+		case abs < 1e17:
+			return time.Unix(0, int64(f*1e3))
+		}
+		return time.Unix(0, int64(f))
+	}
+	return time.Now()
+}
+
+// parseLevel maps the level names of the supported loggers, including the
+// slog offsets such as INFO+2
+func parseLevel(name string) (rogger.Level, bool) {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "+-"); i > 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "trace":
+		return rogger.DebugLevel, true
+	case "dpanic":
+		return rogger.ErrorLevel, true
+	case "panic", "critical":
+		return rogger.FatalLevel, true
+	}
+	level, err := rogger.ParseLevel(name)
+	return level, err == nil
+}
+
+// takeCaller removes the caller from the fields, as the slog source object,
+// the zap caller file:line or the logrus func and file
+func takeCaller(fields map[string]interface{}) *runtime.Frame {
+	if source, ok := fields["source"].(map[string]interface{}); ok {
+		delete(fields, "source")
+		frame := &runtime.Frame{}
+		frame.Function, _ = source["function"].(string)
+		frame.File, _ = source["file"].(string)
+		if line, ok := source["line"].(json.Number); ok {
+			n, _ := line.Int64()
+			frame.Line = int(n)
+		}
+		return frame
+	}
+	if caller, ok := fields["caller"].(string); ok {
+		delete(fields, "caller")
+		return fileFrame(caller)
+	}
+	file, hasFile := fields["file"].(string)
+	function, hasFunc := fields["func"].(string)
+	if !hasFile && !hasFunc {
+		return nil
+	}
+	delete(fields, "file")
+	delete(fields, "func")
+	frame := fileFrame(file)
+	frame.Function = function
+	return frame
+}
+
+// fileFrame reads a file:line location
+func fileFrame(location string) *runtime.Frame {
+	frame := &runtime.Frame{File: location}
+	if i := strings.LastIndexByte(location, ':'); i >= 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil {
+			frame.File, frame.Line = location[:i], line
+		}
+	}
+	return frame
+}
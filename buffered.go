@@ -0,0 +1,157 @@
+package rogger
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// buffered writer defaults
+const (
+	defaultBufferSize    = 64 << 10
+	defaultFlushInterval = time.Second
+)
+
+// BufferedWriter buffers the writes to the output, flushing them once the
+// buffer reaches its size or on every interval, so that high volume logging
+// makes few large writes instead of one per entry. Entries are lost if the
+// process exits without calling Flush or Close, and the buffered bytes are
+// dropped when writing them fails, so that the following writes go on. It is
+// safe for concurrent use.
+type BufferedWriter struct {
+	dst      io.Writer
+	out      *bufio.Writer
+	mu       sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+	closed   bool
+	flushErr error
+
+	// entries from the level are flushed right away when set
+	flushLevel   Level
+	flushOnLevel bool
+}
+
+// NewBufferedWriter returns a writer buffering up to the size in bytes,
+// 64 KiB when not positive, and flushing on every interval, every second
+// when not positive
+func NewBufferedWriter(out io.Writer, size int, interval time.Duration) *BufferedWriter {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	w := &BufferedWriter{
+		dst:  out,
+		out:  bufio.NewWriterSize(out, size),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go w.flushEvery(interval)
+	return w
+}
+
+// Write buffers the bytes, writing the buffer out first when they do not
+// fit. An error of the interval flush is returned by the next write, which
+// still buffers its bytes.
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	n, err := w.out.Write(p)
+	w.resetOnError(err)
+	if flushErr := w.flushErr; flushErr != nil {
+		w.flushErr = nil
+		if err == nil {
+			err = flushErr
+		}
+	}
+	return n, err
+}
+
+// WriteLevel buffers the entry, flushing it right away when it is at or
+// above the flush level
+func (w *BufferedWriter) WriteLevel(level Level, p []byte) (int, error) {
+	n, err := w.Write(p)
+	if n < len(p) {
+		return n, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.flushOnLevel && level >= w.flushLevel && !w.closed {
+		if flushErr := w.flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return n, err
+}
+
+// SetFlushLevel makes the entries at or above the level flush the buffer
+// right away, so that errors are visible without waiting for the interval
+func (w *BufferedWriter) SetFlushLevel(level Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLevel = level
+	w.flushOnLevel = true
+}
+
+// Flush writes the buffered bytes to the output
+func (w *BufferedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// flush writes the buffer out, it must be called with the lock held
+func (w *BufferedWriter) flush() error {
+	err := w.out.Flush()
+	w.resetOnError(err)
+	return err
+}
+
+// resetOnError drops the buffer after a failure, which bufio keeps failing
+// on otherwise
+func (w *BufferedWriter) resetOnError(err error) {
+	if err != nil {
+		w.out.Reset(w.dst)
+	}
+}
+
+// Close flushes the buffer and stops the interval flushes, the writes made
+// afterwards fail. The output is not closed.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.flush()
+	w.mu.Unlock()
+	close(w.stop)
+	<-w.done
+	return err
+}
+
+func (w *BufferedWriter) flushEvery(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			if err := w.flush(); err != nil {
+				w.flushErr = err
+			}
+			w.mu.Unlock()
+		case <-w.stop:
+			return
+		}
+	}
+}
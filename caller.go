@@ -1,3 +1,6 @@
+//go:build !roggerlite
+// +build !roggerlite
+
 package rogger
 
 import (
@@ -8,10 +11,20 @@ import (
 
 var (
 	packageName    string
+	logFunction    string
 	minCallerDepth int
 	callerInitOnce sync.Once
+
+	// resolved frames keyed by program counter, so logging repeatedly from
+	// the same call site skips symbolization
+	frameCache sync.Map
 )
 
+func init() {
+	// start at the bottom of the stack before the package name is cached
+	minCallerDepth = 1
+}
+
 // getPackageName reduces a fully qualified function name to the package name
 func getPackageName(f string) string {
 	for {
@@ -29,21 +42,51 @@ func getPackageName(f string) string {
 func getCaller() *runtime.Frame {
 	// cache this package's fully-qualified name
 	callerInitOnce.Do(func() {
-		pcs := make([]uintptr, 2)
-		packageName = getPackageName(runtime.FuncForPC(pcs[1]).Name())
+		pcs := make([]uintptr, 1)
+		_ = runtime.Callers(1, pcs)
+		frame, _ := runtime.CallersFrames(pcs).Next()
+		packageName = getPackageName(frame.Function)
+		logFunction = packageName + ".Entry.log"
 		minCallerDepth = knownFrames
 	})
 	// Restrict the look back frames to avoid runaway lookups
 	pcs := make([]uintptr, maxCallerDepth)
 	depth := runtime.Callers(minCallerDepth, pcs)
-	frames := runtime.CallersFrames(pcs[:depth])
-	for f, again := frames.Next(); again; f, again = frames.Next() {
-		pkg := getPackageName(f.Function)
-		// If the caller isn't part of this package, we're done
-		if pkg != packageName {
-			return &f
+	// the caller is resolved lazily, possibly from a formatter outside this
+	// package, so only frames beyond the entry log call are considered
+	logged := false
+	for _, pc := range pcs[:depth] {
+		for _, f := range framesForPC(pc) {
+			if !logged {
+				logged = f.Function == logFunction
+				continue
+			}
+			pkg := getPackageName(f.Function)
+			// If the caller isn't part of this package, we're done
+			if pkg != packageName {
+				return &f
+			}
 		}
 	}
 	// if we got here, we failed to find the caller's context
 	return nil
 }
+
+// framesForPC resolves the frames for a program counter, more than one if
+// functions were inlined at it, caching the result
+func framesForPC(pc uintptr) []runtime.Frame {
+	if cached, ok := frameCache.Load(pc); ok {
+		return cached.([]runtime.Frame)
+	}
+	var resolved []runtime.Frame
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := frames.Next()
+		resolved = append(resolved, f)
+		if !more {
+			break
+		}
+	}
+	frameCache.Store(pc, resolved)
+	return resolved
+}
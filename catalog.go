@@ -0,0 +1,45 @@
+package rogger
+
+// the diagnostic strings of the logger, which are the keys to translate
+// them in a Catalog
+const (
+	CatalogFormatFailed     = "Failed to obtain reader, %v"
+	CatalogWriteFailed      = "Failed to write to log, %v"
+	CatalogCrashFileFailed  = "Failed to write crash file, %v"
+	CatalogRecoveredPanic   = "recovered from panic"
+	CatalogAttachmentFailed = "Failed to write attachment, %v"
+)
+
+// Catalog translates the strings the logger writes itself, for operators
+// reading the logs in another language. It is keyed by the english text,
+// the level names as returned by Level.String and the diagnostic strings
+// above, which must keep their formatting verbs.
+//
+//	Catalog{"warn": "avertissement", CatalogWriteFailed: "Échec de l'écriture du journal, %v"}
+//
+// Strings missing from it are kept in english. The level names are only
+// translated by the formatters meant to be read, the TextFormatter and the
+// DevFormatter, the others keep the names other tools understand.
+type Catalog map[string]string
+
+// translate returns the translation of the text, or the text itself
+func (c Catalog) translate(text string) string {
+	if translated, ok := c[text]; ok && translated != "" {
+		return translated
+	}
+	return text
+}
+
+// catalog returns the catalog of the logger of the entry
+func (entry *Entry) catalog() Catalog {
+	if entry.Logger == nil {
+		return nil
+	}
+	return entry.Logger.Catalog
+}
+
+// levelLabel returns the level name of the entry as translated by the
+// catalog of its logger
+func (entry *Entry) levelLabel() string {
+	return entry.catalog().translate(entry.Level.String())
+}
@@ -0,0 +1,232 @@
+package rogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// cbor major types
+const (
+	cborUint   byte = 0
+	cborNegInt byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborTag    byte = 6
+)
+
+// cbor simple values and tags
+const (
+	cborFalse     = 0xf4
+	cborTrue      = 0xf5
+	cborNull      = 0xf6
+	cborFloat32   = 0xfa
+	cborFloat64   = 0xfb
+	cborEpochTime = 1
+)
+
+// CBORFormatter formats entries as CBOR maps for constrained links. The time
+// is encoded as an epoch time tag, the other fixed keys and params the same
+// way as the TextFormatter. Entries are not delimited, as cbor values can be
+// read back to back.
+type CBORFormatter struct {
+	// Disable timestamp logging
+	DisableTimestamp bool
+
+	// EpochTimestamp writes the time as a number of units since the unix
+	// epoch instead of formatting it
+	EpochTimestamp EpochUnit
+
+	// FieldMap renames the fixed keys, such as the time and message
+	FieldMap FieldMap
+
+	// DataKey nests the params under the key when set, so that they never
+	// clash with the fixed keys, which are then not renamed
+	DataKey string
+
+	// CallerPrettyfier returns the function and file written for the caller,
+	// for example to shorten them. Empty values are not written.
+	CallerPrettyfier func(*runtime.Frame) (function, file string)
+}
+
+func (f *CBORFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Params, len(entry.Data)+7)
+	hasCaller := entry.HasCaller()
+	if f.DataKey != "" {
+		if len(entry.Data) > 0 {
+			data[f.DataKey] = entry.Data
+		}
+	} else {
+		for k, v := range entry.Data {
+			data[k] = v
+		}
+		f.FieldMap.fixParamsClash(data, entry)
+	}
+	if !f.DisableTimestamp {
+		if f.EpochTimestamp != EpochNone {
+			data[f.FieldMap.resolve(timeKey)] = f.EpochTimestamp.epoch(entry.Time)
+		} else {
+			data[f.FieldMap.resolve(timeKey)] = entry.Time
+		}
+	}
+	if entry.Message != "" {
+		data[f.FieldMap.resolve(msgKey)] = entry.Message
+	}
+	if entry.EventName != "" {
+		data[f.FieldMap.resolve(eventKey)] = entry.EventName
+	}
+	data[f.FieldMap.resolve(levelKey)] = entry.Level.String()
+	if entry.err != "" {
+		data[f.FieldMap.resolve(errKey)] = entry.err
+	}
+	if hasCaller {
+		function, file := callerFields(entry, f.CallerPrettyfier)
+		if function != "" {
+			data[f.FieldMap.resolve(funcKey)] = function
+		}
+		if file != "" {
+			data[f.FieldMap.resolve(fileKey)] = file
+		}
+	}
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	appendCBOR(buffer, data)
+	return buffer.Bytes(), nil
+}
+
+// appendCBOR encodes the value, values of types with no cbor equivalent are
+// encoded as their string representation
+func appendCBOR(buffer *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteByte(cborNull)
+	case bool:
+		if v {
+			buffer.WriteByte(cborTrue)
+		} else {
+			buffer.WriteByte(cborFalse)
+		}
+	case int:
+		appendCBORInt(buffer, int64(v))
+	case int8:
+		appendCBORInt(buffer, int64(v))
+	case int16:
+		appendCBORInt(buffer, int64(v))
+	case int32:
+		appendCBORInt(buffer, int64(v))
+	case int64:
+		appendCBORInt(buffer, v)
+	case uint:
+		appendCBORHeader(buffer, cborUint, uint64(v))
+	case uint8:
+		appendCBORHeader(buffer, cborUint, uint64(v))
+	case uint16:
+		appendCBORHeader(buffer, cborUint, uint64(v))
+	case uint32:
+		appendCBORHeader(buffer, cborUint, uint64(v))
+	case uint64:
+		appendCBORHeader(buffer, cborUint, v)
+	case float32:
+		buffer.WriteByte(cborFloat32)
+		_ = binary.Write(buffer, binary.BigEndian, math.Float32bits(v))
+	case float64:
+		buffer.WriteByte(cborFloat64)
+		_ = binary.Write(buffer, binary.BigEndian, math.Float64bits(v))
+	case decodedNumber:
+		if i, err := v.Int64(); err == nil {
+			appendCBORInt(buffer, i)
+		} else if f, err := v.Float64(); err == nil {
+			appendCBOR(buffer, f)
+		} else {
+			appendCBORText(buffer, v.String())
+		}
+	case string:
+		appendCBORText(buffer, v)
+	case []byte:
+		appendCBORHeader(buffer, cborBytes, uint64(len(v)))
+		buffer.Write(v)
+	case time.Time:
+		appendCBORHeader(buffer, cborTag, cborEpochTime)
+		if v.Nanosecond() == 0 {
+			appendCBORInt(buffer, v.Unix())
+		} else {
+			appendCBOR(buffer, float64(v.UnixNano())/1e9)
+		}
+	case time.Duration:
+		appendCBORText(buffer, v.String())
+	case error:
+		appendCBORText(buffer, v.Error())
+	case []interface{}:
+		appendCBORHeader(buffer, cborArray, uint64(len(v)))
+		for _, item := range v {
+			appendCBOR(buffer, item)
+		}
+	case []string:
+		appendCBORHeader(buffer, cborArray, uint64(len(v)))
+		for _, item := range v {
+			appendCBORText(buffer, item)
+		}
+	case Params:
+		appendCBORMap(buffer, v)
+	case map[string]interface{}:
+		appendCBORMap(buffer, v)
+	default:
+		appendCBORText(buffer, fmt.Sprint(v))
+	}
+}
+
+func appendCBORMap(buffer *bytes.Buffer, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	appendCBORHeader(buffer, cborMap, uint64(len(m)))
+	for _, k := range keys {
+		appendCBORText(buffer, k)
+		appendCBOR(buffer, m[k])
+	}
+}
+
+func appendCBORText(buffer *bytes.Buffer, s string) {
+	appendCBORHeader(buffer, cborText, uint64(len(s)))
+	buffer.WriteString(s)
+}
+
+func appendCBORInt(buffer *bytes.Buffer, i int64) {
+	if i < 0 {
+		appendCBORHeader(buffer, cborNegInt, uint64(-1-i))
+		return
+	}
+	appendCBORHeader(buffer, cborUint, uint64(i))
+}
+
+// appendCBORHeader writes the major type with its argument in the smallest
+// encoding possible
+func appendCBORHeader(buffer *bytes.Buffer, major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		buffer.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buffer.WriteByte(major | 24)
+		buffer.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buffer.WriteByte(major | 25)
+		_ = binary.Write(buffer, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buffer.WriteByte(major | 26)
+		_ = binary.Write(buffer, binary.BigEndian, uint32(n))
+	default:
+		buffer.WriteByte(major | 27)
+		_ = binary.Write(buffer, binary.BigEndian, n)
+	}
+}
@@ -0,0 +1,133 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cef constants
+const (
+	cefVersion  = 0
+	cefTimeKey  = "rt"
+	maxCEFName  = 512
+	cefNilField = "-"
+)
+
+// CEFFormatter formats entries as ArcSight Common Event Format messages for
+// SIEM ingestion. The message is used as the event name and the params are
+// written as extensions, along with the time, error and caller.
+type CEFFormatter struct {
+	// Device fields of the header
+	Vendor  string
+	Product string
+	Version string
+
+	// SignatureIDKey is the param used as the signature id of the event,
+	// the level name is used when it is not set or missing
+	SignatureIDKey string
+
+	// Severities the levels are mapped with, defaults to CEFSeverities
+	Severities SeverityProfile
+}
+
+func (f *CEFFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Params, len(entry.Data)+4)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	signatureID := entry.Level.String()
+	if id, ok := data[f.SignatureIDKey]; ok && f.SignatureIDKey != "" {
+		signatureID = fmt.Sprint(id)
+		delete(data, f.SignatureIDKey)
+	}
+	name := firstNonEmpty(entry.Message, entry.EventName, entry.Level.String())
+	if len(name) > maxCEFName {
+		name = name[:maxCEFName]
+	}
+
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	_, _ = fmt.Fprintf(buffer, "CEF:%d|%s|%s|%s|%s|%s|%d|",
+		cefVersion,
+		cefHeader(f.Vendor),
+		cefHeader(f.Product),
+		cefHeader(f.Version),
+		cefHeader(signatureID),
+		cefHeader(name),
+		f.Severities.orDefault(CEFSeverities).Severity(entry.Level).Number,
+	)
+
+	hasCaller := entry.HasCaller()
+	fixParamsClash(data, entry)
+	if _, ok := data[cefTimeKey]; ok {
+		data[paramsPrefix+cefTimeKey] = data[cefTimeKey]
+	}
+	data[cefTimeKey] = strconv.FormatInt(entry.Time.UnixNano()/int64(1e6), 10)
+	if entry.EventName != "" {
+		data[eventKey] = entry.EventName
+	}
+	if entry.err != "" {
+		data[errKey] = entry.err
+	}
+	if hasCaller {
+		caller := entry.GetCaller()
+		data[funcKey] = caller.Function
+		data[fileKey] = fmt.Sprintf("%s:%d", caller.File, caller.Line)
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i > 0 {
+			buffer.WriteByte(' ')
+		}
+		buffer.WriteString(cefKey(k))
+		buffer.WriteByte('=')
+		value, ok := data[k].(string)
+		if !ok {
+			value = fmt.Sprint(data[k])
+		}
+		buffer.WriteString(cefExtensionValue(value))
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+var (
+	cefHeaderReplacer    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionReplacer = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// cefHeader escapes a header field
+func cefHeader(value string) string {
+	if value == "" {
+		return cefNilField
+	}
+	return cefHeaderReplacer.Replace(value)
+}
+
+// cefExtensionValue escapes an extension value
+func cefExtensionValue(value string) string {
+	return cefExtensionReplacer.Replace(value)
+}
+
+// cefKey keeps the alphanumeric characters allowed in extension keys
+func cefKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, key)
+	if key == "" {
+		return "unknown"
+	}
+	return key
+}
@@ -0,0 +1,204 @@
+package rogger
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// circuit breaker defaults
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by the CircuitBreakerWriter while its circuit is
+// open
+var ErrCircuitOpen = errors.New("circuit open, output is failing")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+// Circuit states
+const (
+	// CircuitClosed lets the writes through
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails the writes right away
+	CircuitOpen
+
+	// CircuitHalfOpen lets a probe write through to check whether the
+	// output recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the state by name
+func (s CircuitState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// CircuitBreakerStats is a snapshot of a circuit breaker
+type CircuitBreakerStats struct {
+	State CircuitState `json:"state"`
+
+	// number of failures in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// number of writes failed, and rejected while the circuit was open
+	Failures uint64 `json:"failures"`
+	Rejected uint64 `json:"rejected"`
+
+	// number of times the circuit opened
+	Opened uint64 `json:"opened"`
+}
+
+// CircuitBreakerWriter stops writing to an output once it fails repeatedly,
+// such as a dead remote endpoint, so that the writes fail fast with
+// ErrCircuitOpen instead of waiting on it or being retried. After the
+// cooldown, a single write is let through as a probe, closing the circuit
+// when it succeeds and opening it again otherwise. The writes still in
+// flight when the state changes do not change the new one. It is safe for
+// concurrent use.
+type CircuitBreakerWriter struct {
+	// Out written to
+	Out io.Writer
+
+	// Failures in a row opening the circuit, 5 by default
+	Failures int
+
+	// Cooldown the circuit stays open for before a probe, 30 seconds by
+	// default
+	Cooldown time.Duration
+
+	// OnStateChange is called when the circuit changes state, with the
+	// breaker locked, so it must not write to it
+	OnStateChange func(from, to CircuitState)
+
+	mu       sync.Mutex
+	stats    CircuitBreakerStats
+	openedAt time.Time
+	probing  bool
+
+	// generation of the state, changed with it so that the results of the
+	// writes started before are ignored
+	generation uint64
+}
+
+func (w *CircuitBreakerWriter) Write(p []byte) (int, error) {
+	return w.write(func() (int, error) {
+		return w.Out.Write(p)
+	})
+}
+
+func (w *CircuitBreakerWriter) WriteLevel(level Level, p []byte) (int, error) {
+	return w.write(func() (int, error) {
+		return writeLevel(w.Out, level, p)
+	})
+}
+
+// Stats returns a snapshot of the circuit breaker
+func (w *CircuitBreakerWriter) Stats() CircuitBreakerStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// write writes through the circuit when it allows it, and records the result
+func (w *CircuitBreakerWriter) write(write func() (int, error)) (int, error) {
+	generation, ok := w.allow()
+	if !ok {
+		return 0, ErrCircuitOpen
+	}
+	n, err := write()
+	w.record(generation, err)
+	return n, err
+}
+
+// allow checks whether the write can go through, moving to half open once
+// the cooldown is over, and returns the generation of the state it started in
+func (w *CircuitBreakerWriter) allow() (uint64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch w.stats.State {
+	case CircuitOpen:
+		if time.Since(w.openedAt) < w.cooldown() {
+			w.stats.Rejected++
+			return 0, false
+		}
+		w.setState(CircuitHalfOpen)
+		w.probing = true
+	case CircuitHalfOpen:
+		if w.probing {
+			w.stats.Rejected++
+			return 0, false
+		}
+		w.probing = true
+	}
+	return w.generation, true
+}
+
+// record updates the circuit with the result of a write started in the
+// generation, the results of the writes started before the state last
+// changed are only counted
+func (w *CircuitBreakerWriter) record(generation uint64, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if generation != w.generation {
+		if err != nil {
+			w.stats.Failures++
+		}
+		return
+	}
+	w.probing = false
+	if err == nil {
+		w.stats.ConsecutiveFailures = 0
+		if w.stats.State != CircuitClosed {
+			w.setState(CircuitClosed)
+		}
+		return
+	}
+	w.stats.Failures++
+	w.stats.ConsecutiveFailures++
+	if w.stats.State == CircuitHalfOpen || w.stats.ConsecutiveFailures >= w.failures() {
+		w.openedAt = time.Now()
+		w.stats.Opened++
+		w.setState(CircuitOpen)
+	}
+}
+
+// setState changes the state, it must be called with the lock held
+func (w *CircuitBreakerWriter) setState(state CircuitState) {
+	from := w.stats.State
+	w.stats.State = state
+	w.generation++
+	if w.OnStateChange != nil && from != state {
+		w.OnStateChange(from, state)
+	}
+}
+
+func (w *CircuitBreakerWriter) failures() int {
+	if w.Failures <= 0 {
+		return defaultBreakerFailures
+	}
+	return w.Failures
+}
+
+func (w *CircuitBreakerWriter) cooldown() time.Duration {
+	if w.Cooldown <= 0 {
+		return defaultBreakerCooldown
+	}
+	return w.Cooldown
+}
@@ -0,0 +1,93 @@
+package rogger
+
+import (
+	"io"
+	"time"
+)
+
+// Option changes a setting of a logger
+type Option func(*Logger)
+
+// WithLevel sets the logging level
+func WithLevel(level Level) Option {
+	return func(logger *Logger) {
+		logger.Level = level
+	}
+}
+
+// WithFormatter sets the formatter
+func WithFormatter(formatter Formatter) Option {
+	return func(logger *Logger) {
+		logger.Formatter = formatter
+	}
+}
+
+// WithOutput sets the output
+func WithOutput(out io.Writer) Option {
+	return func(logger *Logger) {
+		logger.Out = out
+	}
+}
+
+// WithReportCaller sets whether the caller is reported
+func WithReportCaller(reportCaller bool) Option {
+	return func(logger *Logger) {
+		logger.ReportCaller = reportCaller
+	}
+}
+
+// WithWriteTimeout sets the write timeout
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(logger *Logger) {
+		logger.WriteTimeout = timeout
+	}
+}
+
+// WithErrorHandler sets the handler of the logger's own failures
+func WithErrorHandler(handler func(error)) Option {
+	return func(logger *Logger) {
+		logger.ErrorHandler = handler
+	}
+}
+
+// CloneWith returns a new logger with the settings of this one, changed by
+// the options, so that a library can adjust them without changing the
+// logger shared with the application. The clone has its own stats, history,
+// metric and escalation rules, level change handlers, and its own
+// asynchronous write queue, which has to be closed too. It shares the
+// formatter, output and level outputs with this logger unless they are
+// replaced.
+func (logger *Logger) CloneWith(opts ...Option) *Logger {
+	logger.mu.lock()
+	clone := &Logger{
+		Out:                 logger.Out,
+		Formatter:           logger.Formatter,
+		ReportCaller:        logger.ReportCaller,
+		Level:               logger.Level,
+		WriteTimeout:        logger.WriteTimeout,
+		AsyncQueueSize:      logger.AsyncQueueSize,
+		CrashDir:            logger.CrashDir,
+		HistorySize:         logger.HistorySize,
+		SchemaVersion:       logger.SchemaVersion,
+		Schema:              logger.Schema,
+		ExitFunc:            logger.ExitFunc,
+		ErrorHandler:        logger.ErrorHandler,
+		OnDrop:              logger.OnDrop,
+		OnQueueHighWater:    logger.OnQueueHighWater,
+		QueueHighWater:      logger.QueueHighWater,
+		Attachments:         logger.Attachments,
+		AttachmentThreshold: logger.AttachmentThreshold,
+		Sampler:             logger.Sampler,
+		ParamSources:        logger.ParamSources,
+		StaticParams:        logger.StaticParams,
+		TimestampInUTC:      logger.TimestampInUTC,
+		Catalog:             logger.Catalog,
+	}
+	clone.mu.disabled = logger.mu.disabled
+	clone.levelOutputs = append([]levelOutput(nil), logger.levelOutputs...)
+	logger.mu.unlock()
+	for _, opt := range opts {
+		opt(clone)
+	}
+	return clone
+}
@@ -0,0 +1,78 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// special fields understood by google cloud logging
+const (
+	cloudSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	cloudLabelsKey         = "logging.googleapis.com/labels"
+)
+
+// CloudLoggingFormatter formats entries as the structured json understood by
+// Google Cloud Logging, when written to stdout or stderr on GKE, Cloud Run
+// and similar. The level is reported as the severity, the caller as the
+// source location and the params as labels.
+type CloudLoggingFormatter struct {
+	// Severities the levels are mapped with, defaults to CloudSeverities
+	Severities SeverityProfile
+}
+
+type cloudSourceLocation struct {
+	File     string `json:"file"`
+	Line     string `json:"line"`
+	Function string `json:"function"`
+}
+
+func (f *CloudLoggingFormatter) Format(entry *Entry) ([]byte, error) {
+	payload := map[string]interface{}{
+		"severity": f.Severities.orDefault(CloudSeverities).Severity(entry.Level).Name,
+		"message":  entry.Message,
+		"time":     entry.Time.Format(time.RFC3339Nano),
+	}
+	if entry.EventName != "" {
+		payload[eventKey] = entry.EventName
+	}
+	if entry.err != "" {
+		payload[errKey] = entry.err
+	}
+	if entry.HasCaller() {
+		caller := entry.GetCaller()
+		payload[cloudSourceLocationKey] = cloudSourceLocation{
+			File:     caller.File,
+			Line:     strconv.Itoa(caller.Line),
+			Function: caller.Function,
+		}
+	}
+	if len(entry.Data) > 0 {
+		labels := make(map[string]string, len(entry.Data))
+		for k, v := range entry.Data {
+			switch v := v.(type) {
+			case string:
+				labels[k] = v
+			case error:
+				labels[k] = v.Error()
+			default:
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+		payload[cloudLabelsKey] = labels
+	}
+
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	if err := json.NewEncoder(buffer).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to marshal cloud logging entry, %v", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,122 @@
+// Command rogger pretty prints rogger text or json output from a file or
+// stdin, optionally following the file as it grows.
+//
+//	rogger -f -level warn -fields request_id,user app.log
+//	kubectl logs pod | rogger -no-color
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// options set from the command line
+type options struct {
+	follow          bool
+	level           rogger.Level
+	fields          map[string]bool
+	color           bool
+	timestampFormat string
+}
+
+func main() {
+	follow := flag.Bool("f", false, "keep reading as the file grows")
+	level := flag.String("level", "debug", "minimum level to print")
+	fields := flag.String("fields", "", "comma separated params to print, all by default")
+	noColor := flag.Bool("no-color", false, "disable colors")
+	timestampFormat := flag.String("time-format", time.RFC3339, "timestamp format of text logs")
+	flag.Parse()
+
+	minLevel, err := rogger.ParseLevel(*level)
+	if err != nil {
+		fatal(err)
+	}
+	opts := &options{
+		follow:          *follow,
+		level:           minLevel,
+		color:           !*noColor,
+		timestampFormat: *timestampFormat,
+	}
+	if *fields != "" {
+		opts.fields = make(map[string]bool)
+		for _, field := range strings.Split(*fields, ",") {
+			opts.fields[strings.TrimSpace(field)] = true
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		file, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fatal(err)
+		}
+		defer file.Close()
+		in = file
+	} else {
+		// there is nothing to follow on stdin, it ends when the pipe closes
+		opts.follow = false
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if err := run(in, out, opts); err != nil {
+		out.Flush()
+		fatal(err)
+	}
+}
+
+func run(in io.Reader, out *bufio.Writer, opts *options) error {
+	reader := bufio.NewReader(in)
+	var partial string
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			if !opts.follow {
+				if line = partial + line; line != "" {
+					printLine(out, line, opts)
+				}
+				return nil
+			}
+			// wait for the rest of the line to be written
+			partial += line
+			if err := out.Flush(); err != nil {
+				return err
+			}
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		printLine(out, partial+line, opts)
+		partial = ""
+	}
+}
+
+func printLine(out *bufio.Writer, line string, opts *options) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	rec, err := parseRecord(line, opts.timestampFormat)
+	if err != nil {
+		// not written by rogger, so print it untouched
+		_, _ = fmt.Fprintln(out, paint(line, dim, opts.color))
+		return
+	}
+	if rec.level < opts.level {
+		return
+	}
+	_, _ = out.WriteString(render(rec, opts))
+}
+
+func fatal(err error) {
+	_, _ = fmt.Fprintln(os.Stderr, "rogger:", err)
+	os.Exit(1)
+}
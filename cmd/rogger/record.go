@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+	"github.com/sinhashubham95/rogger/parse"
+)
+
+// record is a log line in either format
+type record struct {
+	time    time.Time
+	level   rogger.Level
+	message string
+	event   string
+	err     string
+	caller  string
+	params  map[string]string
+}
+
+func parseRecord(line, timestampFormat string) (*record, error) {
+	if strings.HasPrefix(line, "{") {
+		return parseJSON(line)
+	}
+	parser := &parse.Parser{TimestampFormat: timestampFormat}
+	entry, err := parser.Line(line)
+	if err != nil {
+		return nil, err
+	}
+	rec := &record{
+		time:    entry.Time,
+		level:   entry.Level,
+		message: entry.Message,
+		event:   entry.Event,
+		err:     entry.Error,
+		caller:  entry.Func,
+		params:  entry.Params,
+	}
+	if entry.File != "" {
+		rec.caller = strings.TrimSpace(fmt.Sprintf("%s %s:%d", entry.Func, entry.File, entry.Line))
+	}
+	return rec, nil
+}
+
+// parseJSON reads entries serialized with Entry.MarshalJSON, with the params
+// nested, as well as flat json where unknown keys are params
+func parseJSON(line string) (*record, error) {
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	levelName, _ := fields["level"].(string)
+	level, err := rogger.ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+	rec := &record{level: level, params: make(map[string]string)}
+	for k, v := range fields {
+		switch k {
+		case "level":
+		case "time":
+			if s, ok := v.(string); ok {
+				rec.time, _ = time.Parse(time.RFC3339Nano, s)
+			}
+		case "message", "msg":
+			rec.message = fmt.Sprint(v)
+		case "event":
+			rec.event = fmt.Sprint(v)
+		case "error":
+			rec.err = fmt.Sprint(v)
+		case "caller":
+			if c, ok := v.(map[string]interface{}); ok {
+				rec.caller = fmt.Sprintf("%v %v:%v", c["function"], c["file"], c["line"])
+			} else {
+				rec.caller = fmt.Sprint(v)
+			}
+		case "params":
+			if params, ok := v.(map[string]interface{}); ok {
+				for pk, pv := range params {
+					rec.params[pk] = stringify(pv)
+				}
+				continue
+			}
+			rec.params[k] = stringify(v)
+		default:
+			rec.params[k] = stringify(v)
+		}
+	}
+	return rec, nil
+}
+
+func stringify(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err == nil {
+			return strings.TrimRight(buf.String(), "\n")
+		}
+	}
+	return fmt.Sprint(v)
+}
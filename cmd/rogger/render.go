@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// ansi colors
+const (
+	reset  = "\x1b[0m"
+	dim    = "\x1b[2m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	blue   = "\x1b[34m"
+	purple = "\x1b[35m"
+	cyan   = "\x1b[36m"
+)
+
+func paint(text, color string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	return color + text + reset
+}
+
+func levelColor(level rogger.Level) string {
+	switch level {
+	case rogger.DebugLevel:
+		return blue
+	case rogger.InfoLevel:
+		return green
+	case rogger.WarnLevel:
+		return yellow
+	case rogger.ErrorLevel:
+		return red
+	}
+	return purple
+}
+
+func render(rec *record, opts *options) string {
+	var b strings.Builder
+	if !rec.time.IsZero() {
+		b.WriteString(paint(rec.time.Format("2006-01-02 15:04:05.000"), dim, opts.color))
+		b.WriteByte(' ')
+	}
+	level := strings.ToUpper(rec.level.String())
+	b.WriteString(paint(level+strings.Repeat(" ", 5-len(level)), levelColor(rec.level), opts.color))
+	if rec.message != "" {
+		b.WriteByte(' ')
+		b.WriteString(strings.TrimRight(rec.message, "\n"))
+	}
+	if rec.event != "" {
+		b.WriteByte(' ')
+		b.WriteString(paint("event="+quote(rec.event), purple, opts.color))
+	}
+	if rec.err != "" {
+		b.WriteByte(' ')
+		b.WriteString(paint("error="+quote(rec.err), red, opts.color))
+	}
+	keys := make([]string, 0, len(rec.params))
+	for k := range rec.params {
+		if opts.fields == nil || opts.fields[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(paint(k, cyan, opts.color))
+		b.WriteByte('=')
+		b.WriteString(quote(rec.params[k]))
+	}
+	if rec.caller != "" && opts.fields == nil {
+		b.WriteByte(' ')
+		b.WriteString(paint(rec.caller, dim, opts.color))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		return strconv.Quote(value)
+	}
+	return value
+}
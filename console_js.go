@@ -0,0 +1,43 @@
+//go:build js && wasm
+// +build js,wasm
+
+package rogger
+
+import (
+	"bytes"
+	"syscall/js"
+)
+
+// ConsoleWriter writes logs to the browser (or node) console when running
+// as WASM. Entries are routed to console.debug, console.info, console.warn
+// or console.error based on their level.
+//
+//	log.SetOutput(rogger.ConsoleWriter{})
+type ConsoleWriter struct{}
+
+// Write logs to console.log as the level is not known
+func (ConsoleWriter) Write(p []byte) (int, error) {
+	return writeConsole("log", p)
+}
+
+// WriteLevel logs to the console method matching the level
+func (ConsoleWriter) WriteLevel(level Level, p []byte) (int, error) {
+	method := "log"
+	switch level {
+	case DebugLevel:
+		method = "debug"
+	case InfoLevel:
+		method = "info"
+	case WarnLevel:
+		method = "warn"
+	case ErrorLevel, FatalLevel:
+		method = "error"
+	}
+	return writeConsole(method, p)
+}
+
+func writeConsole(method string, p []byte) (int, error) {
+	console := js.Global().Get("console")
+	console.Call(method, string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
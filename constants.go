@@ -30,6 +30,7 @@ const (
 	errKey   = "error"
 	funcKey  = "func"
 	fileKey  = "file"
+	eventKey = "event"
 )
 
 // params clash prefix
@@ -0,0 +1,150 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// crash file constants
+const (
+	crashFileTimeFormat = "20060102T150405.000000000"
+	crashDirPerm        = 0755
+	maxCrashStackSize   = 1 << 20
+)
+
+// entryRing keeps the most recent entries logged
+type entryRing struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// add keeps a copy of the entry, dropping the oldest one when the ring is
+// full. The size is taken from the logger on every add, so it can change.
+func (r *entryRing) add(entry *Entry, size int) {
+	if size <= 0 {
+		return
+	}
+	kept := *entry
+	kept.Buffer = nil
+	kept.callerPending = false
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) != size {
+		r.resize(size)
+	}
+	r.entries[r.next] = kept
+	r.next = (r.next + 1) % size
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// resize keeps the most recent entries which fit the new size
+func (r *entryRing) resize(size int) {
+	recent := r.recentLocked()
+	if len(recent) > size {
+		recent = recent[len(recent)-size:]
+	}
+	r.entries = make([]Entry, size)
+	copy(r.entries, recent)
+	r.next = len(recent) % size
+	r.full = len(recent) == size
+}
+
+// recent returns the kept entries, oldest first
+func (r *entryRing) recent() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recentLocked()
+}
+
+func (r *entryRing) recentLocked() []Entry {
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	recent := make([]Entry, 0, len(r.entries))
+	recent = append(recent, r.entries[r.next:]...)
+	return append(recent, r.entries[:r.next]...)
+}
+
+// Recover recovers from a panic, logging it at error level and writing a
+// crash file when a crash directory is set. It must be deferred directly.
+//
+//	defer log.Recover()
+//
+// The panic is logged with the panic.error param when it is an error, the
+// panic.value param otherwise, along with its panic.type and the stack of
+// the goroutine as panic.stack.
+func (logger *Logger) Recover() {
+	if r := recover(); r != nil {
+		entry := logger.WithParams(panicParams(r))
+		entry.crash = true
+		if entry.isLevelEnabled(ErrorLevel) {
+			entry.log(ErrorLevel, staticMessage(logger.Catalog.translate(CatalogRecoveredPanic)))
+		} else {
+			logger.writeCrashFile(entry)
+		}
+	}
+}
+
+// panicParams describes the panic value
+func panicParams(r interface{}) Params {
+	params := Params{
+		"panic.type":  fmt.Sprintf("%T", r),
+		"panic.stack": string(debug.Stack()),
+	}
+	if err, ok := r.(error); ok {
+		params["panic.error"] = err.Error()
+	} else {
+		params["panic.value"] = r
+	}
+	return params
+}
+
+// writeCrashFile writes the final entry, the recent entries and the stacks
+// of all goroutines to a new file in the crash directory. It is written as
+// text independent of the logger formatter and output.
+func (logger *Logger) writeCrashFile(final *Entry) {
+	if logger.CrashDir == "" {
+		return
+	}
+	formatter := &TextFormatter{DisableColors: true}
+	var report bytes.Buffer
+	_, _ = fmt.Fprintf(&report, "crash at %s\n\nfinal entry:\n", time.Now().Format(time.RFC3339Nano))
+	final.Buffer = nil
+	if formatted, err := formatter.Format(final); err == nil {
+		report.Write(formatted)
+	}
+	report.WriteString("\nrecent entries:\n")
+	for _, entry := range logger.history.recent() {
+		if formatted, err := formatter.Format(&entry); err == nil {
+			report.Write(formatted)
+		}
+	}
+	report.WriteString("\ngoroutines:\n")
+	stack := make([]byte, maxCrashStackSize)
+	report.Write(stack[:runtime.Stack(stack, true)])
+
+	name := fmt.Sprintf("crash-%s-%d.log", time.Now().UTC().Format(crashFileTimeFormat), os.Getpid())
+	if err := os.MkdirAll(logger.CrashDir, crashDirPerm); err != nil {
+		logger.handleError(ErrorCrashFile, fmt.Errorf(logger.Catalog.translate(CatalogCrashFileFailed), err))
+		return
+	}
+	file, err := os.OpenFile(filepath.Join(logger.CrashDir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		logger.handleError(ErrorCrashFile, fmt.Errorf(logger.Catalog.translate(CatalogCrashFileFailed), err))
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(report.Bytes()); err != nil {
+		logger.handleError(ErrorCrashFile, fmt.Errorf(logger.Catalog.translate(CatalogCrashFileFailed), err))
+	}
+}
@@ -0,0 +1,94 @@
+package rogger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+)
+
+// CSVFormatter formats entries as csv records with the declared columns, for
+// post processing in spreadsheets. Columns are either the fixed keys, time,
+// message, level, error, func, file and event, or param keys. Missing
+// values are left empty.
+type CSVFormatter struct {
+	// Columns in order
+	Columns []string
+
+	// TimestampFormat to use for the time column and time params
+	TimestampFormat string
+
+	// Comma is the field delimiter, defaults to a comma
+	Comma rune
+}
+
+// Header returns the header record with the column names, to be written
+// once at the start of the output
+func (f *CSVFormatter) Header() []byte {
+	var buffer bytes.Buffer
+	_ = f.write(&buffer, f.Columns)
+	return buffer.Bytes()
+}
+
+func (f *CSVFormatter) Format(entry *Entry) ([]byte, error) {
+	tsFormat := f.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = defaultTimestampFormat
+	}
+	record := make([]string, len(f.Columns))
+	for i, column := range f.Columns {
+		switch column {
+		case timeKey:
+			record[i] = entry.Time.Format(tsFormat)
+		case msgKey:
+			record[i] = entry.Message
+		case levelKey:
+			record[i] = entry.Level.String()
+		case errKey:
+			record[i] = entry.err
+		case eventKey:
+			record[i] = entry.EventName
+		case funcKey:
+			if entry.HasCaller() {
+				record[i] = entry.GetCaller().Function
+			}
+		case fileKey:
+			if entry.HasCaller() {
+				caller := entry.GetCaller()
+				record[i] = fmt.Sprintf("%s:%d", caller.File, caller.Line)
+			}
+		default:
+			switch v := entry.Data[column].(type) {
+			case nil:
+			case string:
+				record[i] = v
+			case time.Time:
+				record[i] = v.Format(tsFormat)
+			case error:
+				record[i] = v.Error()
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+	}
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	if err := f.write(buffer, record); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (f *CSVFormatter) write(buffer *bytes.Buffer, record []string) error {
+	writer := csv.NewWriter(buffer)
+	if f.Comma != 0 {
+		writer.Comma = f.Comma
+	}
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
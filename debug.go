@@ -0,0 +1,45 @@
+//go:build roggerdebug
+// +build roggerdebug
+
+// The roggerdebug build tag enables runtime assertions finding misuse of
+// the logger while developing, which panic describing it. They catch
+// entries used after they were released to the pool, the params of an entry
+// modified after it was created, which may be shared with other entries and
+// goroutines. They are left
+// out of other builds, which pay nothing for them.
+//
+//    go test -race -tags roggerdebug ./...
+
+package rogger
+
+// entryDebug is the state of an entry the assertions check
+type entryDebug struct {
+	released bool
+
+	// number of params of the entry when it was created, plus one, zero
+	// when unknown
+	params int
+}
+
+func (entry *Entry) debugAcquire() {
+	entry.debug.released = false
+}
+
+func (entry *Entry) debugRelease() {
+	entry.debug.released = true
+}
+
+// debugSeal records the params of a new entry
+func (entry *Entry) debugSeal() {
+	entry.debug.params = len(entry.Data) + 1
+}
+
+// debugCheck asserts the entry can be used
+func (entry *Entry) debugCheck() {
+	if entry.debug.released {
+		panic("rogger: entry used after it was released")
+	}
+	if entry.debug.params != 0 && entry.debug.params != len(entry.Data)+1 {
+		panic("rogger: params of an entry modified after it was created, use WithParams instead")
+	}
+}
@@ -0,0 +1,157 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// dev formatter layout
+const (
+	devTimestampFormat = "15:04:05.000"
+	devLevelWidth      = 5
+	devIndent          = "    "
+)
+
+// DevFormatter formats entries for reading in a terminal while developing,
+//
+//	15:04:05.000 INFO  request served (handler.go:42)
+//	    latency=12ms
+//	    user=admin
+//
+// the level is padded to a fixed width so the messages line up, and every
+// param is written on its own indented line. Values spanning multiple lines,
+// like stack traces, are written verbatim below their key instead of being
+// quoted into a single line. It is not meant to be parsed, use the
+// TextFormatter or LogfmtFormatter for that.
+type DevFormatter struct {
+	// TimestampFormat to use for display, only the time of day by default
+	TimestampFormat string
+
+	// Levels are colored and timestamps are dimmed when the output is a
+	// terminal. ForceColors colors them for any output, and DisableColors
+	// never does.
+	ForceColors   bool
+	DisableColors bool
+
+	// Theme styles the output when colored, DarkTheme by default
+	Theme *Theme
+
+	// whether the output is a terminal, checked on the first format
+	terminalOnce sync.Once
+	isTerminal   bool
+}
+
+func (f *DevFormatter) Format(entry *Entry) ([]byte, error) {
+	colored := f.isColored(entry)
+	theme := f.Theme.orDefault(&DarkTheme)
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	tsFormat := f.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = devTimestampFormat
+	}
+
+	// header with the time, level, message and caller
+	writeColored(buffer, colored, theme.Timestamp, entry.Time.Format(tsFormat))
+	buffer.WriteByte(' ')
+	level := strings.ToUpper(entry.levelLabel())
+	writeColored(buffer, colored, theme.level(entry.Level), level)
+	if width := utf8.RuneCountInString(level); width < devLevelWidth {
+		buffer.WriteString(strings.Repeat(" ", devLevelWidth-width))
+	}
+	buffer.WriteByte(' ')
+	buffer.WriteString(firstNonEmpty(entry.Message, entry.EventName))
+	if entry.HasCaller() {
+		caller := entry.GetCaller()
+		buffer.WriteByte(' ')
+		writeColored(buffer, colored, theme.Caller, fmt.Sprintf("(%s:%d)", filepath.Base(caller.File), caller.Line))
+	}
+	buffer.WriteByte('\n')
+
+	// the params, each on an indented line
+	data := make(Params)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	fixParamsClash(data, entry)
+	if entry.EventName != "" && entry.Message != "" {
+		appendDevParam(buffer, colored, theme, eventKey, entry.EventName)
+	}
+	if entry.err != "" {
+		appendDevParam(buffer, colored, theme, errKey, entry.err)
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendDevParam(buffer, colored, theme, k, data[k])
+	}
+	return buffer.Bytes(), nil
+}
+
+// isColored checks whether the entry should be colored
+func (f *DevFormatter) isColored(entry *Entry) bool {
+	if f.DisableColors {
+		return false
+	}
+	if f.ForceColors {
+		return true
+	}
+	f.terminalOnce.Do(func() {
+		if entry.Logger != nil {
+			f.isTerminal = isTerminal(entry.output())
+		}
+	})
+	return f.isTerminal
+}
+
+// appendDevParam writes a param on its own line, with multi line values
+// written verbatim and indented below the key
+func appendDevParam(buffer *bytes.Buffer, colored bool, theme *Theme, key string, value interface{}) {
+	stringVal, ok := value.(string)
+	if !ok {
+		stringVal = fmt.Sprint(value)
+	}
+	style := ""
+	if _, isErr := value.(error); isErr || key == errKey {
+		style = theme.Error
+	}
+	buffer.WriteString(devIndent)
+	writeColored(buffer, colored, theme.Key, key)
+	if !strings.Contains(stringVal, "\n") {
+		buffer.WriteByte('=')
+		if stringVal == "" {
+			stringVal = `""`
+		}
+		writeColored(buffer, colored, style, stringVal)
+		buffer.WriteByte('\n')
+		return
+	}
+	buffer.WriteString(":\n")
+	for _, line := range strings.Split(strings.TrimRight(stringVal, "\n"), "\n") {
+		buffer.WriteString(devIndent)
+		buffer.WriteString(devIndent)
+		writeColored(buffer, colored, style, line)
+		buffer.WriteByte('\n')
+	}
+}
+
+// writeColored writes the text in the color when colored
+func writeColored(buffer *bytes.Buffer, colored bool, color, text string) {
+	if !colored || color == "" {
+		buffer.WriteString(text)
+		return
+	}
+	buffer.WriteString(color)
+	buffer.WriteString(text)
+	buffer.WriteString(colorReset)
+}
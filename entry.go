@@ -2,10 +2,11 @@ package rogger
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"reflect"
 	"runtime"
 	"sync"
 	"time"
@@ -25,6 +26,7 @@ type Entry struct {
 	Logger *Logger
 
 	// all the params set by the user
+	// it may be shared between derived entries, so it must not be modified
 	Data Params
 
 	// time at which log was created
@@ -34,16 +36,39 @@ type Entry struct {
 	Level Level
 
 	// calling method with package name
+	// it is resolved lazily, so use GetCaller to read it from a formatter
 	Caller *runtime.Frame
 
 	// log message
 	Message string
 
+	// name of the event, for entries logged with Event
+	EventName string
+
+	// context of the entry, which can scope the logging level
+	Context context.Context
+
 	// When formatter is called in entry.log(), a Buffer may be set to entry
 	Buffer *bytes.Buffer
 
 	// err may contain a field formatting error
 	err string
+
+	// out overrides the logger output for this entry
+	out io.Writer
+
+	// group prefixes the keys of the params added to the entry
+	group string
+
+	// whether a crash file is written for the entry, fatal entries always
+	// write one
+	crash bool
+
+	// whether the caller should be resolved when it is first requested
+	callerPending bool
+
+	// state checked by the roggerdebug assertions
+	debug entryDebug
 }
 
 func init() {
@@ -52,8 +77,6 @@ func init() {
 			return new(bytes.Buffer)
 		},
 	}
-	// start at the bottom of the stack before the package name is cached
-	minCallerDepth = 1
 }
 
 func NewEntry(logger *Logger) *Entry {
@@ -64,7 +87,18 @@ func NewEntry(logger *Logger) *Entry {
 }
 
 func (entry *Entry) HasCaller() bool {
-	return entry.Logger != nil && entry.Logger.ReportCaller && entry.Caller != nil
+	return entry.Logger != nil && entry.Logger.ReportCaller && entry.GetCaller() != nil
+}
+
+// GetCaller returns the calling method of the entry. When caller reporting is
+// on, the lookup only happens the first time this is called while the entry
+// is being logged, so formatters not using the caller don't pay for it.
+func (entry *Entry) GetCaller() *runtime.Frame {
+	if entry.callerPending {
+		entry.callerPending = false
+		entry.Caller = getCaller()
+	}
+	return entry.Caller
 }
 
 func (entry *Entry) String() (string, error) {
@@ -90,22 +124,13 @@ func (entry *Entry) WithParam(key string, value interface{}) *Entry {
 
 // Add a map of params to the Entry
 func (entry *Entry) WithParams(params Params) *Entry {
-	data := make(Params, len(entry.Data)+len(params))
-	for k, v := range entry.Data {
-		data[k] = v
-	}
+	entry.debugCheck()
+	// the data is shared with the parent entry until a field is added
+	data := entry.Data
+	copied := false
 	err := entry.err
 	for k, v := range params {
-		isErrField := false
-		if t := reflect.TypeOf(v); t != nil {
-			switch t.Kind() {
-			case reflect.Func:
-				isErrField = true
-			case reflect.Ptr:
-				isErrField = t.Elem().Kind() == reflect.Func
-			}
-		}
-		if isErrField {
+		if isFuncParam(v) {
 			tmp := fmt.Sprintf("can not add field %q", k)
 			if err != "" {
 				err = entry.err + ", " + tmp
@@ -113,79 +138,322 @@ func (entry *Entry) WithParams(params Params) *Entry {
 				err = tmp
 			}
 		} else {
-			data[k] = v
+			if !copied {
+				data = make(Params, len(entry.Data)+len(params))
+				for dk, dv := range entry.Data {
+					data[dk] = dv
+				}
+				copied = true
+			}
+			data[entry.group+k] = v
 		}
 	}
-	return &Entry{
-		Logger:  entry.Logger,
-		Data:    data,
-		Time:    entry.Time,
-		Level:   entry.Level,
-		Caller:  entry.Caller,
-		Message: entry.Message,
-		Buffer:  entry.Buffer,
-		err:     err,
+	derived := &Entry{
+		Logger:    entry.Logger,
+		Data:      data,
+		Time:      entry.Time,
+		Level:     entry.Level,
+		Caller:    entry.Caller,
+		Message:   entry.Message,
+		EventName: entry.EventName,
+		Context:   entry.Context,
+		Buffer:    entry.Buffer,
+		err:       err,
+		out:       entry.out,
+		group:     entry.group,
+	}
+	derived.debugSeal()
+	return derived
+}
+
+// WithGroup adds the params to a group, with their keys prefixed by its
+// name and a dot, like http.method and http.status for the http group. The
+// params added to the returned entry are in the group too, and nested
+// groups are joined with dots.
+func (entry *Entry) WithGroup(name string, params Params) *Entry {
+	grouped := entry
+	if name != "" {
+		grouped = &Entry{
+			Logger:    entry.Logger,
+			Data:      entry.Data,
+			Time:      entry.Time,
+			Level:     entry.Level,
+			Caller:    entry.Caller,
+			Message:   entry.Message,
+			EventName: entry.EventName,
+			Context:   entry.Context,
+			Buffer:    entry.Buffer,
+			err:       entry.err,
+			out:       entry.out,
+			group:     entry.group + name + ".",
+		}
+	}
+	if len(params) == 0 {
+		return grouped
 	}
+	return grouped.WithParams(params)
 }
 
 // Overrides the time of the log entry.
 func (entry *Entry) WithTime(t time.Time) *Entry {
 	return &Entry{
-		Logger:  entry.Logger,
-		Data:    entry.Data,
-		Time:    t,
-		Level:   entry.Level,
-		Caller:  entry.Caller,
-		Message: entry.Message,
-		Buffer:  entry.Buffer,
-		err:     entry.err,
+		Logger:    entry.Logger,
+		Data:      entry.Data,
+		Time:      t,
+		Level:     entry.Level,
+		Caller:    entry.Caller,
+		Message:   entry.Message,
+		EventName: entry.EventName,
+		Context:   entry.Context,
+		Buffer:    entry.Buffer,
+		err:       entry.err,
+		out:       entry.out,
+		group:     entry.group,
+	}
+}
+
+// Add a context to the Entry, a minimum level set on the context using
+// Logger.WithMinLevel applies to the entry.
+func (entry *Entry) WithContext(ctx context.Context) *Entry {
+	return &Entry{
+		Logger:    entry.Logger,
+		Data:      entry.Data,
+		Time:      entry.Time,
+		Level:     entry.Level,
+		Caller:    entry.Caller,
+		Message:   entry.Message,
+		EventName: entry.EventName,
+		Context:   ctx,
+		Buffer:    entry.Buffer,
+		err:       entry.err,
+		out:       entry.out,
+		group:     entry.group,
+	}
+}
+
+// Overrides the output of the log entry, the logger output is used for
+// all other entries. Writes are still synchronized with the logger.
+func (entry *Entry) WithOutput(w io.Writer) *Entry {
+	return &Entry{
+		Logger:    entry.Logger,
+		Data:      entry.Data,
+		Time:      entry.Time,
+		Level:     entry.Level,
+		Caller:    entry.Caller,
+		Message:   entry.Message,
+		EventName: entry.EventName,
+		Context:   entry.Context,
+		Buffer:    entry.Buffer,
+		err:       entry.err,
+		out:       w,
+		group:     entry.group,
 	}
 }
 
 func (entry *Entry) checkLoggerAttached() bool {
-	if entry.checkLoggerAttached() {
+	if entry.Logger == nil {
 		_, _ = fmt.Fprintln(os.Stderr, "Logger not attached")
 		return true
 	}
 	return false
 }
 
+// withDefaultParams returns the data merged over the defaults, copying it
+// only when there are some
+func withDefaultParams(data Params, defaults Params) Params {
+	if len(defaults) == 0 {
+		return data
+	}
+	merged := make(Params, len(defaults)+len(data))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return merged
+}
+
+// staticMessage returns the builder of a message known in advance
+func staticMessage(msg string) func() string {
+	return func() string {
+		return msg
+	}
+}
+
+// log writes the entry. The message is only built once the level is known
+// to be enabled, so that disabled entries do not pay for formatting it. The
+// entries dropped by the sampler still do, as it tells them apart by message
+// after the escalation rules, which need it too.
 // This function is not declared with a pointer value because otherwise
 // race conditions will occur when using multiple goroutines
-func (entry Entry) log(l Level, msg string) {
-	var buffer *bytes.Buffer
+func (entry Entry) log(l Level, buildMessage func() string) {
+	entry.debugCheck()
+	if !entry.prepare(l, buildMessage) {
+		return
+	}
 
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	defer bufferPool.Put(buffer)
+	entry.Buffer = buffer
+
+	entry.write()
+
+	entry.Buffer = nil
+	entry.written()
+}
+
+// prepare completes the entry to be written at the level, with its time,
+// params and message, and reports whether it is written, which it is not
+// when the sampler drops it
+func (entry *Entry) prepare(l Level, buildMessage func() string) bool {
 	if entry.Time.IsZero() {
 		entry.Time = time.Now()
 	}
+	if entry.Logger.TimestampInUTC {
+		entry.Time = entry.Time.UTC()
+	}
 
 	entry.Level = l
-	entry.Message = msg
+	call := entry.Data
+	entry.Data = withDefaultParams(entry.Data, entry.Logger.StaticParams)
 	if entry.Logger.ReportCaller {
-		entry.Caller = getCaller()
+		entry.Caller = nil
+		entry.callerPending = true
 	}
 
-	buffer = bufferPool.Get().(*bytes.Buffer)
-	buffer.Reset()
-	defer bufferPool.Put(buffer)
-	entry.Buffer = buffer
+	entry.Message = buildMessage()
+	entry.Logger.escalations.escalate(entry)
+	// the entries dropped by the sampler are not counted or attached
+	sampler, rate := entry.Logger.Sampler, 1
+	if sampler != nil {
+		var keep bool
+		if rate, keep = sampler.sample(entry); !keep {
+			if entry.Logger.OnDrop != nil {
+				entry.Logger.OnDrop(entry, DropSampled)
+			}
+			return false
+		}
+	}
+	entry.Logger.counters.countLevel(entry.Level)
+	entry.Logger.metrics.observe(entry)
+	// the static params are validated with the ones of the entry, so that the
+	// required ones can be static, but not the ones the logger adds below
+	if entry.Logger.Schema != nil {
+		for _, err := range entry.Logger.Schema.validate(entry.Data) {
+			entry.Logger.handleError(ErrorSchema, err)
+		}
+	}
 
-	entry.write()
+	var sources map[string]string
+	if entry.Logger.ParamSources {
+		sources = entry.Logger.paramSources(call)
+	}
+	entry.Data = entry.Logger.attach(entry.Data, sources)
+	if version := entry.Logger.SchemaVersion; version != "" {
+		entry.Data = withParam(entry.Data, schemaVersionKey, version)
+		overrideSource(sources, schemaVersionKey)
+	}
+	if rate > 1 {
+		entry.Data = withParam(entry.Data, sampler.rateKey(), rate)
+		overrideSource(sources, sampler.rateKey())
+	}
+	if sources != nil {
+		entry.Data = withParam(entry.Data, paramSourcesKey, formatSources(sources))
+	}
+	return true
+}
 
-	entry.Buffer = nil
+// written keeps the written entry in the history, and writes the crash file
+// of fatal entries
+func (entry *Entry) written() {
+	if entry.crash || entry.Level == FatalLevel {
+		entry.Logger.writeCrashFile(entry)
+	}
+	entry.Logger.history.add(entry, entry.Logger.HistorySize)
+}
+
+// output returns the writer the entry should be written to
+func (entry *Entry) output() io.Writer {
+	if entry.out != nil {
+		return entry.out
+	}
+	return entry.Logger.Out
 }
 
 func (entry *Entry) write() {
 	entry.Logger.mu.lock()
 	defer entry.Logger.mu.unlock()
+	start := time.Now()
+	defer func() {
+		entry.Logger.writeLatency.observe(time.Since(start))
+	}()
 	formattedLog, err := entry.Logger.Formatter.Format(entry)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
+		entry.Logger.handleError(ErrorFormat, fmt.Errorf(entry.Logger.Catalog.translate(CatalogFormatFailed), err))
+		return
+	}
+	if routed := entry.routedOutputs(); len(routed) > 0 {
+		for _, out := range routed {
+			entry.writeTo(out, formattedLog)
+		}
+		return
+	}
+	entry.writeTo(entry.output(), formattedLog)
+}
+
+// writeTo writes the formatted entry to the writer, reporting failures
+func (entry *Entry) writeTo(out io.Writer, formattedLog []byte) {
+	if w, ok := out.(entryWriter); ok {
+		w.writeEntry(entry, formattedLog)
+		return
+	}
+	if entry.Logger.AsyncQueueSize > 0 && entry.Logger.writeAsync(out, entry, formattedLog) {
+		return
+	}
+	var err error
+	if entry.Logger.WriteTimeout > 0 {
+		err = entry.Logger.writeWithTimeout(out, entry, formattedLog)
 	} else {
-		_, err = entry.Logger.Out.Write(formattedLog)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		_, err = writeLevel(out, entry.Level, formattedLog)
+	}
+	if err == ErrWriteTimeout {
+		entry.Logger.handleError(ErrorWriteTimeout, err)
+	} else if err == ErrQueueFull {
+		entry.Logger.handleError(ErrorQueueFull, err)
+		if entry.Logger.OnDrop != nil {
+			entry.Logger.OnDrop(entry, DropQueueFull)
 		}
+	} else if err != nil {
+		entry.Logger.handleError(ErrorWrite, fmt.Errorf(entry.Logger.Catalog.translate(CatalogWriteFailed), err))
+	}
+}
+
+// isLevelEnabled checks the level against the logger level, and the scoped
+// level of the entry context if any
+func (entry *Entry) isLevelEnabled(level Level) bool {
+	if entry.Logger.IsLevelEnabled(level) {
+		return true
+	}
+	if entry.Context != nil {
+		if min, ok := entry.Context.Value(minLevelKey{entry.Logger}).(Level); ok {
+			return level >= min
+		}
+	}
+	return false
+}
+
+// Event logs a structured event at info level, with the name as a dedicated
+// field and no message
+func (entry *Entry) Event(name string, params Params) {
+	if entry.checkLoggerAttached() {
+		return
+	}
+	if entry.isLevelEnabled(InfoLevel) {
+		event := entry.WithParams(params)
+		event.EventName = name
+		event.log(InfoLevel, staticMessage(""))
 	}
 }
 
@@ -194,8 +462,10 @@ func (entry *Entry) Log(level Level, args ...interface{}) {
 		_, _ = fmt.Fprintln(os.Stderr, "Logger not attached")
 		return
 	}
-	if entry.Logger.IsLevelEnabled(level) {
-		entry.log(level, fmt.Sprint(args...))
+	if entry.isLevelEnabled(level) {
+		entry.log(level, func() string {
+			return fmt.Sprint(args...)
+		})
 	}
 }
 
@@ -211,6 +481,11 @@ func (entry *Entry) Warn(args ...interface{}) {
 	entry.Log(WarnLevel, args...)
 }
 
+// Warning is an alias of Warn
+func (entry *Entry) Warning(args ...interface{}) {
+	entry.Warn(args...)
+}
+
 func (entry *Entry) Error(args ...interface{}) {
 	entry.Log(ErrorLevel, args...)
 }
@@ -229,8 +504,10 @@ func (entry *Entry) Logf(level Level, format string, args ...interface{}) {
 		_, _ = fmt.Fprintln(os.Stderr, "Logger not attached")
 		return
 	}
-	if entry.Logger.IsLevelEnabled(level) {
-		entry.log(level, fmt.Sprintf(format, args...))
+	if entry.isLevelEnabled(level) {
+		entry.log(level, func() string {
+			return fmt.Sprintf(format, args...)
+		})
 	}
 }
 
@@ -246,6 +523,11 @@ func (entry *Entry) Warnf(format string, args ...interface{}) {
 	entry.Logf(WarnLevel, format, args...)
 }
 
+// Warningf is an alias of Warnf
+func (entry *Entry) Warningf(format string, args ...interface{}) {
+	entry.Warnf(format, args...)
+}
+
 func (entry *Entry) Errorf(format string, args ...interface{}) {
 	entry.Logf(ErrorLevel, format, args...)
 }
@@ -264,8 +546,10 @@ func (entry *Entry) Logln(level Level, args ...interface{}) {
 		_, _ = fmt.Fprintln(os.Stderr, "Logger not attached")
 		return
 	}
-	if entry.Logger.IsLevelEnabled(level) {
-		entry.log(level, fmt.Sprintln(args...))
+	if entry.isLevelEnabled(level) {
+		entry.log(level, func() string {
+			return fmt.Sprintln(args...)
+		})
 	}
 }
 
@@ -281,6 +565,11 @@ func (entry *Entry) Warnln(args ...interface{}) {
 	entry.Logln(WarnLevel, args...)
 }
 
+// Warningln is an alias of Warnln
+func (entry *Entry) Warningln(args ...interface{}) {
+	entry.Warnln(args...)
+}
+
 func (entry *Entry) Errorln(args ...interface{}) {
 	entry.Logln(ErrorLevel, args...)
 }
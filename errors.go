@@ -0,0 +1,63 @@
+package rogger
+
+// ErrorCode classifies the failures of the logger, so that operators can
+// alert on specific ones
+type ErrorCode int
+
+// error codes
+const (
+	// ErrorFormat is a formatter failing to format an entry
+	ErrorFormat ErrorCode = iota + 1
+
+	// ErrorWrite is the output failing to write an entry
+	ErrorWrite
+
+	// ErrorWriteTimeout is a write timing out, it continues in the
+	// background
+	ErrorWriteTimeout
+
+	// ErrorQueueFull is an entry dropped as the background writes queue is
+	// full
+	ErrorQueueFull
+
+	// ErrorCrashFile is a crash file failing to be written
+	ErrorCrashFile
+
+	// ErrorSchema is an entry violating the schema of the logger
+	ErrorSchema
+
+	// ErrorAttachment is a param value failing to be written to the
+	// attachments, it is logged as it is
+	ErrorAttachment
+)
+
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrorFormat:
+		return "format"
+	case ErrorWrite:
+		return "write"
+	case ErrorWriteTimeout:
+		return "write_timeout"
+	case ErrorQueueFull:
+		return "queue_full"
+	case ErrorCrashFile:
+		return "crash_file"
+	case ErrorSchema:
+		return "schema"
+	case ErrorAttachment:
+		return "attachment"
+	}
+	return "unknown"
+}
+
+// Error is a failure of the logger, as passed to the error handler. Err is
+// the underlying error, such as ErrWriteTimeout or a *SchemaError.
+type Error struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
@@ -0,0 +1,136 @@
+package rogger
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEscalationKeys bounds the messages tracked by an escalation rule, the
+// ones not seen within the window are forgotten beyond it
+const maxEscalationKeys = 1024
+
+// EscalationRule raises the level of entries whose message, with its error
+// if any, is logged more than Threshold times within Window, turning noisy
+// warnings into actionable errors.
+//
+//	logger.AddEscalationRule(EscalationRule{
+//		MinLevel:  WarnLevel,
+//		Threshold: 100,
+//		Window:    time.Minute,
+//		Level:     ErrorLevel,
+//	})
+type EscalationRule struct {
+	// MinLevel entries must be logged at to be counted
+	MinLevel Level
+
+	// Threshold is the number of occurrences within the window after which
+	// entries are escalated
+	Threshold int
+
+	// Window the occurrences are counted in
+	Window time.Duration
+
+	// Level the escalated entries are raised to, entries already at or
+	// above it keep theirs. Entries raised to fatal are not exiting.
+	Level Level
+
+	// OnEscalate is called with every escalated entry, for example to page
+	// someone. It must not log through the same logger at a level the rule
+	// counts.
+	OnEscalate func(*Entry)
+}
+
+// escalation is a rule with the recent occurrences of each message
+type escalation struct {
+	rule EscalationRule
+	seen map[string][]time.Time
+}
+
+// escalationRules holds the escalation rules of a logger
+type escalationRules struct {
+	mu          sync.Mutex
+	escalations []*escalation
+}
+
+// AddEscalationRule adds a rule escalating repeated entries
+func (logger *Logger) AddEscalationRule(rule EscalationRule) {
+	logger.escalations.mu.Lock()
+	defer logger.escalations.mu.Unlock()
+	logger.escalations.escalations = append(logger.escalations.escalations, &escalation{
+		rule: rule,
+		seen: make(map[string][]time.Time),
+	})
+}
+
+// escalate counts the entry against the rules, raising its level when one
+// is exceeded, and calls their OnEscalate
+func (r *escalationRules) escalate(entry *Entry) {
+	var fired []func(*Entry)
+	r.mu.Lock()
+	if len(r.escalations) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	key := escalationKey(entry)
+	for _, e := range r.escalations {
+		if entry.Level < e.rule.MinLevel || e.rule.Threshold <= 0 {
+			continue
+		}
+		if e.observe(key, entry.Time) <= e.rule.Threshold {
+			continue
+		}
+		if e.rule.Level > entry.Level {
+			entry.Level = e.rule.Level
+		}
+		if e.rule.OnEscalate != nil {
+			fired = append(fired, e.rule.OnEscalate)
+		}
+	}
+	r.mu.Unlock()
+
+	// the callbacks are called unlocked, so that they can use the logger
+	for _, onEscalate := range fired {
+		onEscalate(entry)
+	}
+}
+
+// observe records an occurrence of the key and returns the number of them
+// within the window, up to one more than the threshold
+func (e *escalation) observe(key string, now time.Time) int {
+	if _, ok := e.seen[key]; !ok && len(e.seen) >= maxEscalationKeys {
+		e.forget(now)
+	}
+	times := e.seen[key]
+	start := 0
+	for start < len(times) && now.Sub(times[start]) > e.rule.Window {
+		start++
+	}
+	// only the occurrences needed to exceed the threshold are kept
+	times = append(times[start:], now)
+	if len(times) > e.rule.Threshold+1 {
+		times = times[len(times)-e.rule.Threshold-1:]
+	}
+	e.seen[key] = times
+	return len(times)
+}
+
+// forget drops the keys with no occurrence within the window
+func (e *escalation) forget(now time.Time) {
+	for key, times := range e.seen {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) > e.rule.Window {
+			delete(e.seen, key)
+		}
+	}
+}
+
+// escalationKey identifies the entries counted together
+func escalationKey(entry *Entry) string {
+	key := firstNonEmpty(entry.Message, entry.EventName)
+	if err, ok := entry.Data[errKey].(error); ok {
+		key += "\x00" + err.Error()
+	}
+	if entry.err != "" {
+		key += "\x00" + entry.err
+	}
+	return key
+}
@@ -0,0 +1,125 @@
+//go:build windows && !roggerlite
+// +build windows,!roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// event log entry types
+const (
+	eventLogError       = 0x0001
+	eventLogWarning     = 0x0002
+	eventLogInformation = 0x0004
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSource   = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEvent           = advapi32.NewProc("ReportEventW")
+)
+
+// EventLogWriter writes the entries to the Windows Event Log, for services
+// running without access to files. The levels are mapped to the event
+// types, errors and fatal entries are errors, warnings are warnings and the
+// others are information, and the formatted entries are the event
+// messages. The event source is registered on the first write. It is safe
+// for concurrent use.
+type EventLogWriter struct {
+	// Source of the events, usually the service name. It should be
+	// registered in the registry, for example with New-EventLog, for the
+	// messages to display without a warning.
+	Source string
+
+	// EventID of the events, 1 by default
+	EventID uint32
+
+	mu     sync.Mutex
+	handle uintptr
+}
+
+// Write writes the bytes as an information event
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+func (w *EventLogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.handle == 0 {
+		if err := w.register(); err != nil {
+			return 0, err
+		}
+	}
+	message, err := syscall.UTF16PtrFromString(string(bytes.TrimRight(bytes.Replace(p, []byte{0}, nil, -1), "\r\n")))
+	if err != nil {
+		return 0, err
+	}
+	eventID := w.EventID
+	if eventID == 0 {
+		eventID = 1
+	}
+	messages := []*uint16{message}
+	r, _, err := procReportEvent.Call(
+		w.handle,
+		uintptr(eventType(level)),
+		0,
+		uintptr(eventID),
+		0,
+		uintptr(len(messages)),
+		0,
+		uintptr(unsafe.Pointer(&messages[0])),
+		0,
+	)
+	if r == 0 {
+		return 0, fmt.Errorf("failed to report event, %v", err)
+	}
+	return len(p), nil
+}
+
+// Close deregisters the event source, it is registered again on the next
+// write
+func (w *EventLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.handle == 0 {
+		return nil
+	}
+	r, _, err := procDeregisterEventSource.Call(w.handle)
+	w.handle = 0
+	if r == 0 {
+		return fmt.Errorf("failed to deregister event source, %v", err)
+	}
+	return nil
+}
+
+// register registers the event source, it must be called with the lock held
+func (w *EventLogWriter) register() error {
+	source, err := syscall.UTF16PtrFromString(w.Source)
+	if err != nil {
+		return err
+	}
+	handle, _, err := procRegisterEventSource.Call(0, uintptr(unsafe.Pointer(source)))
+	if handle == 0 {
+		return fmt.Errorf("failed to register event source %q, %v", w.Source, err)
+	}
+	w.handle = handle
+	return nil
+}
+
+// eventType returns the event log type of the level
+func eventType(level Level) uint16 {
+	switch {
+	case level >= ErrorLevel:
+		return eventLogError
+	case level >= WarnLevel:
+		return eventLogWarning
+	}
+	return eventLogInformation
+}
@@ -0,0 +1,56 @@
+package rogger
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FieldLogger is implemented by both Logger and Entry, so code can depend on
+// it instead of the concrete types, to swap or mock the logger.
+type FieldLogger interface {
+	WithParam(key string, value interface{}) *Entry
+	WithParams(params Params) *Entry
+	WithError(err error) *Entry
+
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Warning(args ...interface{})
+	Error(args ...interface{})
+	Fatal(args ...interface{})
+
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+
+	Debugln(args ...interface{})
+	Infoln(args ...interface{})
+	Warnln(args ...interface{})
+	Warningln(args ...interface{})
+	Errorln(args ...interface{})
+	Fatalln(args ...interface{})
+}
+
+// Ext1FieldLogger extends FieldLogger with logging at a given level and the
+// remaining entry options.
+type Ext1FieldLogger interface {
+	FieldLogger
+
+	WithTime(t time.Time) *Entry
+	WithContext(ctx context.Context) *Entry
+	WithOutput(w io.Writer) *Entry
+
+	Log(level Level, args ...interface{})
+	Logf(level Level, format string, args ...interface{})
+	Logln(level Level, args ...interface{})
+}
+
+// the logger and entry must implement the interfaces
+var (
+	_ Ext1FieldLogger = (*Logger)(nil)
+	_ Ext1FieldLogger = (*Entry)(nil)
+)
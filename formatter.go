@@ -1,45 +1,182 @@
 package rogger
 
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
 type Formatter interface {
 	Format(*Entry) ([]byte, error)
 }
 
+// the fixed keys of the output, which can be renamed with a FieldMap
+const (
+	FieldKeyTime    = timeKey
+	FieldKeyMessage = msgKey
+	FieldKeyLevel   = levelKey
+	FieldKeyError   = errKey
+	FieldKeyFunc    = funcKey
+	FieldKeyFile    = fileKey
+	FieldKeyEvent   = eventKey
+)
+
+// FieldMap renames the fixed keys of the output to match a downstream
+// schema, for example
+//
+//	FieldMap{FieldKeyTime: "ts", FieldKeyMessage: "msg"}
+//
+// keys that are not in it keep their name.
+type FieldMap map[string]string
+
+// resolve returns the name the key is written with
+func (f FieldMap) resolve(key string) string {
+	if name, ok := f[key]; ok && name != "" {
+		return name
+	}
+	return key
+}
+
 // this is to avoid missing fields such as time, msg, etc. which are
 // added by default
-func fixParamsClash(data Params, reportCaller bool) {
-	// time key check
-	if t, ok := data[timeKey]; ok {
-		data[paramsPrefix+timeKey] = t
-		delete(data, timeKey)
+func fixParamsClash(data Params, entry *Entry) {
+	FieldMap(nil).fixParamsClash(data, entry)
+}
+
+// fixParamsClash moves the params named like the fixed keys, as renamed by
+// the field map, to the params prefix
+func (f FieldMap) fixParamsClash(data Params, entry *Entry) {
+	keys := []string{timeKey, msgKey, levelKey, errKey}
+
+	// the event key is only written for events
+	if entry.EventName != "" {
+		keys = append(keys, eventKey)
 	}
 
-	// msg key check
-	if m, ok := data[msgKey]; ok {
-		data[paramsPrefix+msgKey] = m
-		delete(data, msgKey)
+	// func and file are only written with the caller
+	if entry.HasCaller() {
+		keys = append(keys, funcKey, fileKey)
 	}
 
-	// level key check
-	if l, ok := data[levelKey]; ok {
-		data[paramsPrefix+levelKey] = l
-		delete(data, levelKey)
+	for _, key := range keys {
+		key = f.resolve(key)
+		if v, ok := data[key]; ok {
+			data[paramsPrefix+key] = v
+			delete(data, key)
+		}
 	}
+}
 
-	// err key check
-	if e, ok := data[errKey]; ok {
-		data[paramsPrefix+errKey] = e
-		delete(data, errKey)
+// callerFields returns the function and file:line of the caller of the
+// entry, as prettified by the function when it is set
+func callerFields(entry *Entry, prettyfier func(*runtime.Frame) (function, file string)) (string, string) {
+	caller := entry.GetCaller()
+	if prettyfier != nil {
+		return prettyfier(caller)
 	}
+	return caller.Function, fmt.Sprintf("%s:%d", caller.File, caller.Line)
+}
 
-	// func and file check
-	if reportCaller {
-		if fu, ok := data[funcKey]; ok {
-			data[paramsPrefix+funcKey] = fu
-			delete(data, funcKey)
-		}
-		if fi, ok := data[fileKey]; ok {
-			data[paramsPrefix+fileKey] = fi
-			delete(data, fileKey)
-		}
+// EpochUnit is the unit of timestamps written as numbers since the unix
+// epoch, which some backends require and are cheaper to parse
+type EpochUnit int
+
+// epoch units, EpochNone writes formatted timestamps
+const (
+	EpochNone EpochUnit = iota
+	EpochSeconds
+	EpochMillis
+	EpochMicros
+	EpochNanos
+)
+
+// epoch returns the time as a number of units since the unix epoch
+func (u EpochUnit) epoch(t time.Time) int64 {
+	switch u {
+	case EpochSeconds:
+		return t.Unix()
+	case EpochMillis:
+		return t.UnixNano() / int64(time.Millisecond)
+	case EpochMicros:
+		return t.UnixNano() / int64(time.Microsecond)
+	}
+	return t.UnixNano()
+}
+
+// truncate cuts the text to the maximum length in bytes when it is longer,
+// noting the original length, zero for no maximum
+func truncate(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes)", text[:cut], len(text))
+}
+
+// truncateString truncates the value when it is a string or an error,
+// keeping the other values as they are
+func truncateString(value interface{}, max int) interface{} {
+	if max <= 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return truncate(v, max)
+	case error:
+		return truncate(v.Error(), max)
+	}
+	return value
+}
+
+// truncateText truncates the string representation of the value
+func truncateText(value interface{}, max int) interface{} {
+	if max <= 0 {
+		return value
+	}
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	return truncate(text, max)
+}
+
+// NewlineMode is how line oriented formatters write the newlines of messages
+// and values, which break the consumers reading an entry per line
+type NewlineMode int
+
+// newline modes, escaping them by default
+const (
+	// NewlineEscape writes newlines as \n
+	NewlineEscape NewlineMode = iota
+
+	// NewlineReplace replaces newlines with spaces
+	NewlineReplace
+
+	// NewlineKeep writes newlines as they are, for consumers handling
+	// entries spanning multiple lines
+	NewlineKeep
+)
+
+var (
+	newlineEscaper  = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+	newlineReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+)
+
+// apply writes the newlines of the text according to the mode
+func (m NewlineMode) apply(text string) string {
+	if !strings.ContainsAny(text, "\r\n") {
+		return text
+	}
+	switch m {
+	case NewlineEscape:
+		return newlineEscaper.Replace(text)
+	case NewlineReplace:
+		return newlineReplacer.Replace(text)
 	}
+	return text
 }
@@ -0,0 +1,124 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// GELFFormatter formats entries as Graylog Extended Log Format payloads,
+// so that they can be sent to Graylog directly. The params are added as
+// additional fields, prefixed with an underscore.
+type GELFFormatter struct {
+	// Host reported in the payload, defaults to the hostname
+	Host string
+
+	// Severities the levels are mapped with, defaults to SyslogSeverities
+	Severities SeverityProfile
+
+	// NullTerminated ends every payload with a null byte instead of a
+	// new line, as required by GELF over TCP
+	NullTerminated bool
+
+	hostOnce sync.Once
+	hostname string
+}
+
+// gelfVersion is the version of the format implemented
+const gelfVersion = "1.1"
+
+func (f *GELFFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Params, len(entry.Data))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	hasCaller := entry.HasCaller()
+	fixParamsClash(data, entry)
+
+	shortMessage := firstNonEmpty(entry.Message, entry.EventName, entry.Level.String())
+	payload := map[string]interface{}{
+		"version":       gelfVersion,
+		"host":          f.host(),
+		"short_message": shortMessage,
+		"timestamp":     float64(entry.Time.UnixNano()/int64(1e6)) / 1e3,
+		"level":         f.Severities.orDefault(SyslogSeverities).Severity(entry.Level).Number,
+	}
+	if i := strings.IndexByte(entry.Message, '\n'); i >= 0 {
+		payload["short_message"] = entry.Message[:i]
+		payload["full_message"] = entry.Message
+	}
+	if entry.EventName != "" {
+		payload["_"+eventKey] = entry.EventName
+	}
+	if entry.err != "" {
+		payload["_"+errKey] = entry.err
+	}
+	if hasCaller {
+		caller := entry.GetCaller()
+		payload["_"+funcKey] = caller.Function
+		payload["_"+fileKey] = fmt.Sprintf("%s:%d", caller.File, caller.Line)
+	}
+	for k, v := range data {
+		key := "_" + gelfKey(k)
+		if key == "_id" {
+			// _id is reserved by graylog
+			key = "_" + paramsPrefix + "id"
+		}
+		payload[key] = gelfValue(v)
+	}
+
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	if err := json.NewEncoder(buffer).Encode(payload); err != nil {
+		return nil, fmt.Errorf("failed to marshal gelf payload, %v", err)
+	}
+	if f.NullTerminated {
+		buffer.Bytes()[buffer.Len()-1] = 0
+	}
+	return buffer.Bytes(), nil
+}
+
+func (f *GELFFormatter) host() string {
+	if f.Host != "" {
+		return f.Host
+	}
+	f.hostOnce.Do(func() {
+		f.hostname, _ = os.Hostname()
+		if f.hostname == "" {
+			f.hostname = "unknown"
+		}
+	})
+	return f.hostname
+}
+
+// gelfKey replaces the characters not allowed in gelf field names
+func gelfKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '_' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// gelfValue converts a param to a string or number, the only types allowed
+// for additional fields
+func gelfValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, decodedNumber:
+		return v
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
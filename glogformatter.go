@@ -0,0 +1,84 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// glog header constants
+const (
+	glogTimeFormat  = "0102 15:04:05.000000"
+	glogUnknownFile = "???"
+)
+
+// GlogFormatter formats entries with the header used by glog and klog,
+//
+//	I0102 15:04:05.000000    1234 file.go:42] message key=value
+//
+// so services moving from them keep their parsing and alerting rules. The
+// caller is only known when it is reported, and debug entries use the info
+// letter like klog verbose logs. Params are appended like the TextFormatter.
+type GlogFormatter struct {
+	// NewlineMode is how newlines in the message are written, escaped by
+	// default. Params with newlines are quoted with them escaped.
+	NewlineMode NewlineMode
+}
+
+func (f *GlogFormatter) Format(entry *Entry) ([]byte, error) {
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	file, line := glogUnknownFile, 1
+	if entry.HasCaller() {
+		caller := entry.GetCaller()
+		file, line = filepath.Base(caller.File), caller.Line
+	}
+	_, _ = fmt.Fprintf(buffer, "%c%s %7d %s:%d] %s",
+		glogLevel(entry.Level),
+		entry.Time.Format(glogTimeFormat),
+		os.Getpid(),
+		file,
+		line,
+		f.NewlineMode.apply(firstNonEmpty(entry.Message, entry.EventName)),
+	)
+
+	// the params are written after the message, which is not a pair
+	var params bytes.Buffer
+	if entry.EventName != "" && entry.Message != "" {
+		appendData(&params, eventKey, entry.EventName)
+	}
+	if entry.err != "" {
+		appendData(&params, errKey, entry.err)
+	}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendData(&params, k, entry.Data[k])
+	}
+	if params.Len() > 0 {
+		buffer.WriteByte(' ')
+		buffer.Write(params.Bytes())
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// glogLevel returns the letter a level starts the line with
+func glogLevel(level Level) byte {
+	switch level {
+	case DebugLevel, InfoLevel:
+		return 'I'
+	case WarnLevel:
+		return 'W'
+	case ErrorLevel:
+		return 'E'
+	}
+	return 'F'
+}
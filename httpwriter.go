@@ -0,0 +1,231 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// http writer defaults
+const (
+	defaultHTTPBatchSize   = 100
+	defaultHTTPTimeout     = 10 * time.Second
+	defaultHTTPContentType = "application/x-ndjson"
+)
+
+// defaultHTTPClient posts the batches of the writers without a client
+var defaultHTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+
+// HTTPWriter ships the entries to an http endpoint, such as a log collector
+// only accepting http. The formatted entries are batched, and each batch is
+// posted as their concatenation once it holds BatchSize entries, or on every
+// FlushInterval. A batch failing to be posted is dropped and its error
+// returned by the write which posted it, or the next one. The writes posting
+// a batch wait for it, so the logger should be asynchronous to not hold the
+// callers back. Entries are lost if the process exits without calling Flush
+// or Close. It is safe for concurrent use.
+//
+//	logger.SetOutput(&HTTPWriter{
+//		URL:    "https://logs.example.com/ingest",
+//		Header: http.Header{"Authorization": {"Bearer " + token}},
+//		Gzip:   true,
+//	})
+type HTTPWriter struct {
+	// URL the batches are posted to
+	URL string
+
+	// Header is added to the requests. The content type is
+	// application/x-ndjson unless it sets another.
+	Header http.Header
+
+	// BatchSize is the number of entries posted together, 100 by default
+	BatchSize int
+
+	// FlushInterval is the delay after which the entries are posted even
+	// though the batch is not full, the default flush interval when zero
+	FlushInterval time.Duration
+
+	// Gzip compresses the batches
+	Gzip bool
+
+	// Client posts the batches, a client with a 10 seconds timeout by
+	// default
+	Client *http.Client
+
+	mu       sync.Mutex
+	body     []byte
+	count    int
+	flushErr error
+	closed   bool
+	stop     chan struct{}
+
+	// held while posting, so that the batches are posted in order
+	postMu sync.Mutex
+}
+
+// Write adds the bytes to the batch, posting it once full
+func (w *HTTPWriter) Write(p []byte) (int, error) {
+	return len(p), w.add([][]byte{p})
+}
+
+// WriteBatch adds the entries to the batch, posting it once full
+func (w *HTTPWriter) WriteBatch(entries [][]byte) error {
+	return w.add(entries)
+}
+
+// Flush posts the batch
+func (w *HTTPWriter) Flush() error {
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+	w.mu.Lock()
+	body, err := w.take()
+	w.mu.Unlock()
+	return firstError(err, w.post(body))
+}
+
+// Close posts the batch and stops the interval flushes, the writes fail
+// afterwards
+func (w *HTTPWriter) Close() error {
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.stop != nil {
+		close(w.stop)
+	}
+	body, err := w.take()
+	w.mu.Unlock()
+	return firstError(err, w.post(body))
+}
+
+// add appends the entries to the batch, and posts it once it is full
+func (w *HTTPWriter) add(entries [][]byte) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return os.ErrClosed
+	}
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+		go w.flushEvery(w.stop)
+	}
+	for _, p := range entries {
+		w.body = append(w.body, p...)
+	}
+	w.count += len(entries)
+	full := w.count >= w.batchSize()
+	err := w.flushErr
+	w.flushErr = nil
+	w.mu.Unlock()
+	if !full {
+		return err
+	}
+	return firstError(err, w.Flush())
+}
+
+// take returns the batch and the error of the last interval flush, and
+// starts a new batch. It must be called with the lock held.
+func (w *HTTPWriter) take() ([]byte, error) {
+	body, err := w.body, w.flushErr
+	w.body, w.count, w.flushErr = nil, 0, nil
+	return body, err
+}
+
+// flushEvery posts the batch on every interval until stopped
+func (w *HTTPWriter) flushEvery(stop chan struct{}) {
+	interval := w.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				w.mu.Lock()
+				w.flushErr = err
+				w.mu.Unlock()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// post sends the body to the url
+func (w *HTTPWriter) post(body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	var reader io.Reader = bytes.NewReader(body)
+	if w.Gzip {
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(body); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		reader = &compressed
+	}
+	req, err := http.NewRequest(http.MethodPost, w.URL, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range w.Header {
+		req.Header[key] = values
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", defaultHTTPContentType)
+	}
+	if w.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	client := w.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	// the body is read to the end so that the connection is reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to post logs to %s, %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+func (w *HTTPWriter) batchSize() int {
+	if w.BatchSize <= 0 {
+		return defaultHTTPBatchSize
+	}
+	return w.BatchSize
+}
+
+// firstError returns the first of the errors which is not nil
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+//go:build linux && !roggerlite
+// +build linux,!roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// socket of the native journal protocol
+var journalAddr = &net.UnixAddr{Name: "/run/systemd/journal/socket", Net: "unixgram"}
+
+// journal fields written by the JournaldWriter, which params are not
+// allowed to override
+var journalFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+	"ERROR":             true,
+	"EVENT":             true,
+	"SYSLOG_IDENTIFIER": true,
+}
+
+// JournaldWriter writes the entries to the systemd journal with its native
+// protocol, so that their params are kept as journal fields, queryable with
+// journalctl, instead of being flattened into the message. The level is the
+// PRIORITY field, the caller the CODE_FILE, CODE_LINE and CODE_FUNC ones,
+// and the params are named in upper case with the characters not allowed
+// replaced by underscores, PARAM_ prefixed when they clash with the fields
+// above. Set as the logger output, the output of the logger formatter is
+// not used. It is safe for concurrent use.
+type JournaldWriter struct {
+	// SyslogIdentifier of the entries, the executable name by default
+	SyslogIdentifier string
+
+	// Severities the levels are mapped with, defaults to SyslogSeverities
+	Severities SeverityProfile
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+}
+
+// Write sends the bytes as the message of an info entry
+func (w *JournaldWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+// WriteLevel sends the bytes as the message of an entry at the level
+func (w *JournaldWriter) WriteLevel(level Level, p []byte) (int, error) {
+	var payload bytes.Buffer
+	appendJournalField(&payload, "MESSAGE", string(bytes.TrimRight(p, "\n")))
+	w.appendHeader(&payload, level)
+	if _, err := w.send(payload.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeEntry sends the entry with its params as fields
+func (w *JournaldWriter) writeEntry(entry *Entry, _ []byte) {
+	var payload bytes.Buffer
+	message := entry.Message
+	if message == "" {
+		message = entry.EventName
+	}
+	appendJournalField(&payload, "MESSAGE", message)
+	w.appendHeader(&payload, entry.Level)
+	if entry.EventName != "" {
+		appendJournalField(&payload, "EVENT", entry.EventName)
+	}
+	if entry.err != "" {
+		appendJournalField(&payload, "ERROR", entry.err)
+	}
+	if entry.HasCaller() {
+		caller := entry.GetCaller()
+		appendJournalField(&payload, "CODE_FILE", caller.File)
+		appendJournalField(&payload, "CODE_LINE", strconv.Itoa(caller.Line))
+		appendJournalField(&payload, "CODE_FUNC", caller.Function)
+	}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, ok := entry.Data[k].(string)
+		if !ok {
+			value = fmt.Sprint(entry.Data[k])
+		}
+		appendJournalField(&payload, journalFieldName(k), value)
+	}
+	entry.writeTo(journalSender{w}, payload.Bytes())
+}
+
+// Close closes the connection to the journal, it is made again on the next
+// write
+func (w *JournaldWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *JournaldWriter) appendHeader(payload *bytes.Buffer, level Level) {
+	appendJournalField(payload, "PRIORITY", strconv.Itoa(w.Severities.orDefault(SyslogSeverities).Severity(level).Number))
+	appendJournalField(payload, "SYSLOG_IDENTIFIER", firstNonEmpty(w.SyslogIdentifier, filepath.Base(os.Args[0])))
+}
+
+// send writes the payload as a datagram, or through a file descriptor when
+// it is too big for one
+func (w *JournaldWriter) send(payload []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		// the socket is not connected, as descriptors can not be passed on
+		// connected datagram sockets
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+		if err != nil {
+			return 0, fmt.Errorf("failed to connect to the journal, %v", err)
+		}
+		w.conn = conn
+	}
+	n, err := w.conn.WriteToUnix(payload, journalAddr)
+	if err == nil {
+		return n, nil
+	}
+	if opErr, ok := err.(*net.OpError); !ok || !isMessageTooLong(opErr.Err) {
+		return n, err
+	}
+	return w.sendFile(payload)
+}
+
+// sendFile passes the payload in an unlinked temporary file, as the journal
+// protocol expects for payloads too big for a datagram
+func (w *JournaldWriter) sendFile(payload []byte) (int, error) {
+	file, err := ioutil.TempFile("/dev/shm", "rogger-journal-")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	_ = os.Remove(file.Name())
+	if _, err = file.Write(payload); err != nil {
+		return 0, err
+	}
+	_, _, err = w.conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), journalAddr)
+	if err != nil {
+		return 0, err
+	}
+	return len(payload), nil
+}
+
+// isMessageTooLong checks for the errors of datagrams too big to be sent
+func isMessageTooLong(err error) bool {
+	if sysErr, ok := err.(*os.SyscallError); ok {
+		err = sysErr.Err
+	}
+	return err == syscall.EMSGSIZE || err == syscall.ENOBUFS
+}
+
+// journalSender writes the payloads of a JournaldWriter
+type journalSender struct {
+	w *JournaldWriter
+}
+
+func (s journalSender) Write(p []byte) (int, error) {
+	return s.w.send(p)
+}
+
+// appendJournalField writes the field in the native protocol format, with
+// the value length prefixed when it spans multiple lines
+func appendJournalField(payload *bytes.Buffer, name, value string) {
+	payload.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		payload.WriteByte('=')
+		payload.WriteString(value)
+		payload.WriteByte('\n')
+		return
+	}
+	payload.WriteByte('\n')
+	_ = binary.Write(payload, binary.LittleEndian, uint64(len(value)))
+	payload.WriteString(value)
+	payload.WriteByte('\n')
+}
+
+// journalFieldName converts the param key to a valid journal field name
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || journalFields[name] {
+		name = "PARAM_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,49 @@
+package rogger
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+)
+
+// JournalFormatter prefixes the lines of another formatter with the <N>
+// syslog priority of the level, following the sd-daemon convention, so that
+// journald reading the standard output of a service under systemd stores
+// and colors the entries by their level.
+type JournalFormatter struct {
+	// Formatter writing the lines, defaults to a TextFormatter without
+	// timestamps and colors, as the journal records and colors them itself
+	Formatter Formatter
+
+	// Severities the levels are mapped with, defaults to SyslogSeverities
+	Severities SeverityProfile
+
+	defaultOnce sync.Once
+	formatter   Formatter
+}
+
+func (f *JournalFormatter) Format(entry *Entry) ([]byte, error) {
+	f.defaultOnce.Do(func() {
+		f.formatter = f.Formatter
+		if f.formatter == nil {
+			f.formatter = &TextFormatter{DisableTimestamp: true, DisableColors: true}
+		}
+	})
+	formatted, err := f.formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	prefix := "<" + strconv.Itoa(f.Severities.orDefault(SyslogSeverities).Severity(entry.Level).Number) + ">"
+
+	// the formatted bytes can be in the entry buffer, so the lines are
+	// copied out, each one prefixed as journald reads them separately
+	lines := bytes.SplitAfter(bytes.TrimSuffix(formatted, []byte{'\n'}), []byte{'\n'})
+	var out bytes.Buffer
+	out.Grow(len(formatted) + len(lines)*len(prefix) + 1)
+	for _, line := range lines {
+		out.WriteString(prefix)
+		out.Write(line)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
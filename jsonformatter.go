@@ -0,0 +1,131 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+)
+
+// JSONFormatter formats entries as single line json objects, with the fixed
+// keys and the params side by side like the TextFormatter. Errors are
+// written as their message, and params which can not be encoded as json as
+// their string representation.
+type JSONFormatter struct {
+	// Disable timestamp logging
+	DisableTimestamp bool
+
+	// TimestampFormat to use for display when a full timestamp is printed
+	TimestampFormat string
+
+	// EpochTimestamp writes the time as a number of units since the unix
+	// epoch instead of formatting it
+	EpochTimestamp EpochUnit
+
+	// FieldMap renames the fixed keys, such as the time and message
+	FieldMap FieldMap
+
+	// LevelText customizes the level names, such as writing them in upper
+	// case. They are not padded.
+	LevelText LevelText
+
+	// MaxMessageLength and MaxValueLength truncate the messages and the
+	// string param values longer than them, in bytes, noting their original
+	// length. Zero is for no maximum.
+	MaxMessageLength int
+	MaxValueLength   int
+
+	// PrettyPrint indents the objects over multiple lines for reading them
+	// while debugging. They are no longer json lines, which most tools
+	// reading logs expect.
+	PrettyPrint bool
+
+	// DisableHTMLEscape keeps <, > and & as they are instead of escaping
+	// them, so that urls and html in params stay readable
+	DisableHTMLEscape bool
+
+	// DataKey nests the params under the key when set, so that they never
+	// clash with the fixed keys, which are then not renamed
+	DataKey string
+
+	// CallerPrettyfier returns the function and file written for the caller,
+	// for example to shorten them. Empty values are not written.
+	CallerPrettyfier func(*runtime.Frame) (function, file string)
+}
+
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Params, len(entry.Data)+7)
+	params := data
+	if f.DataKey != "" {
+		params = make(Params, len(entry.Data))
+		if len(entry.Data) > 0 {
+			data[f.DataKey] = params
+		}
+	}
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		params[k] = truncateString(v, f.MaxValueLength)
+	}
+	hasCaller := entry.HasCaller()
+	if f.DataKey == "" {
+		f.FieldMap.fixParamsClash(data, entry)
+	}
+	if !f.DisableTimestamp {
+		tsFormat := f.TimestampFormat
+		if tsFormat == "" {
+			tsFormat = defaultTimestampFormat
+		}
+		if f.EpochTimestamp != EpochNone {
+			data[f.FieldMap.resolve(timeKey)] = f.EpochTimestamp.epoch(entry.Time)
+		} else {
+			data[f.FieldMap.resolve(timeKey)] = entry.Time.Format(tsFormat)
+		}
+	}
+	if entry.Message != "" {
+		data[f.FieldMap.resolve(msgKey)] = truncate(entry.Message, f.MaxMessageLength)
+	}
+	if entry.EventName != "" {
+		data[f.FieldMap.resolve(eventKey)] = entry.EventName
+	}
+	data[f.FieldMap.resolve(levelKey)] = f.LevelText.name(entry.Level, nil)
+	if entry.err != "" {
+		data[f.FieldMap.resolve(errKey)] = truncate(entry.err, f.MaxValueLength)
+	}
+	if hasCaller {
+		function, file := callerFields(entry, f.CallerPrettyfier)
+		if function != "" {
+			data[f.FieldMap.resolve(funcKey)] = function
+		}
+		if file != "" {
+			data[f.FieldMap.resolve(fileKey)] = file
+		}
+	}
+
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(!f.DisableHTMLEscape)
+	if f.PrettyPrint {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		// the encoder writes nothing on failure, so the values which can
+		// not be encoded are replaced and it is tried again
+		for k, v := range params {
+			if _, err := json.Marshal(v); err != nil {
+				params[k] = fmt.Sprint(v)
+			}
+		}
+		if err = encoder.Encode(data); err != nil {
+			return nil, fmt.Errorf("failed to marshal json, %v", err)
+		}
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,127 @@
+package jsonl
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// maxLineSize is the size of the longest line that can be read
+const maxLineSize = 1 << 20
+
+// RangeReader reads the lines of a store with a time in a range. Lines
+// without a readable time are skipped.
+type RangeReader struct {
+	options Options
+	from    time.Time
+	to      time.Time
+	file    *os.File
+	scanner *bufio.Scanner
+	line    []byte
+	done    bool
+}
+
+// ReadRange opens the store at the path and seeks to the lines with a time
+// between from and to, both included, a zero time leaving that end of the
+// range open. It uses the default options.
+func ReadRange(path string, from, to time.Time) (*RangeReader, error) {
+	return ReadRangeWithOptions(path, from, to, Options{})
+}
+
+// ReadRangeWithOptions is ReadRange for a store opened with other options
+func ReadRangeWithOptions(path string, from, to time.Time, options Options) (*RangeReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := seekOffset(IndexPath(path), from)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineSize)
+	return &RangeReader{
+		options: options.withDefaults(),
+		from:    from,
+		to:      to,
+		file:    file,
+		scanner: scanner,
+	}, nil
+}
+
+// seekOffset finds the offset of the last index point before the time, so
+// that no line of the range is before it. It is the start of the file
+// without an index.
+func seekOffset(indexPath string, from time.Time) (int64, error) {
+	index, err := os.Open(indexPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = index.Close()
+	}()
+	stat, err := index.Stat()
+	if err != nil {
+		return 0, err
+	}
+	n := int(stat.Size() / indexRecordSize)
+	var readErr error
+	i := sort.Search(n, func(i int) bool {
+		record, err := readIndexRecord(index, int64(i))
+		if err != nil {
+			readErr = err
+			return true
+		}
+		return record.time >= from.UnixNano()
+	})
+	if readErr != nil || i == 0 {
+		return 0, readErr
+	}
+	record, err := readIndexRecord(index, int64(i-1))
+	return record.offset, err
+}
+
+// Scan advances to the next line of the range, it is false at the end of
+// the range or on an error
+func (r *RangeReader) Scan() bool {
+	for !r.done && r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		t, ok := lineTime(line, r.options)
+		if !ok || t.Before(r.from) {
+			continue
+		}
+		if !r.to.IsZero() && t.After(r.to) {
+			r.done = true
+			break
+		}
+		r.line = line
+		return true
+	}
+	r.line = nil
+	return false
+}
+
+// Bytes returns the current line, valid until the next call to Scan
+func (r *RangeReader) Bytes() []byte {
+	return r.line
+}
+
+// Err returns the error which stopped the reader, if any
+func (r *RangeReader) Err() error {
+	return r.scanner.Err()
+}
+
+// Close closes the file
+func (r *RangeReader) Close() error {
+	return r.file.Close()
+}
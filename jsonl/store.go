@@ -0,0 +1,225 @@
+// Package jsonl stores entries as json lines in an append only file, with a
+// sparse index of their times next to it, so that the entries of a time
+// range can be read from large files without scanning them fully.
+//
+//	store, err := jsonl.Open("service.log", jsonl.Options{})
+//	if err != nil {
+//		...
+//	}
+//	defer store.Close()
+//	logger.SetFormatter(&rogger.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+//	logger.SetOutput(store)
+//
+// and later
+//
+//	reader, err := jsonl.ReadRange("service.log", from, to)
+//	for reader.Scan() {
+//		line := reader.Bytes()
+//	}
+//
+// The index assumes entries are written in time order, as they are by a
+// single logger.
+package jsonl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// store defaults
+const (
+	defaultIndexInterval = 64 << 10
+	defaultTimeKey       = "time"
+	defaultTimeFormat    = time.RFC3339Nano
+	indexSuffix          = ".idx"
+
+	// an index record is the unix nano time and the offset of a line
+	indexRecordSize = 16
+)
+
+// Options of a store, the same options must be used to read it
+type Options struct {
+	// IndexInterval is the number of bytes written between two index
+	// points, 64 KiB by default. Smaller intervals make reading a range
+	// faster for a bigger index.
+	IndexInterval int64
+
+	// TimeKey is the key of the time in the lines, "time" by default
+	TimeKey string
+
+	// TimeFormat is the layout of the time in the lines, RFC 3339 by
+	// default, with or without fractional seconds
+	TimeFormat string
+}
+
+func (o Options) withDefaults() Options {
+	if o.IndexInterval <= 0 {
+		o.IndexInterval = defaultIndexInterval
+	}
+	if o.TimeKey == "" {
+		o.TimeKey = defaultTimeKey
+	}
+	if o.TimeFormat == "" {
+		o.TimeFormat = defaultTimeFormat
+	}
+	return o
+}
+
+// IndexPath returns the path of the index of the store at the path
+func IndexPath(path string) string {
+	return path + indexSuffix
+}
+
+// Store is an output which appends the lines written to it to a file, and
+// records the time and offset of a line every index interval to the index
+// file. Every write is expected to be complete lines, like the formatted
+// entries of a logger.
+type Store struct {
+	options Options
+
+	mu          sync.Mutex
+	file        *os.File
+	index       *os.File
+	offset      int64
+	lastIndexed int64
+	indexed     bool
+}
+
+// Open opens the store at the path for appending, creating the file and its
+// index if they do not exist
+func Open(path string, options Options) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(IndexPath(path), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	s := &Store{options: options.withDefaults(), file: file, index: index}
+	if s.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	// continue the index from its last point
+	stat, err := index.Stat()
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	if n := stat.Size() / indexRecordSize; n > 0 {
+		record, err := readIndexRecord(index, n-1)
+		if err != nil {
+			_ = s.Close()
+			return nil, err
+		}
+		s.lastIndexed, s.indexed = record.offset, true
+	}
+	return s, nil
+}
+
+func (s *Store) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, err := s.file.Write(p)
+	if n > 0 {
+		// the index is written after the lines, so that it never points
+		// past the end of the file
+		if indexErr := s.indexLines(p[:n]); err == nil {
+			err = indexErr
+		}
+	}
+	return n, err
+}
+
+// indexLines adds the lines starting at an index interval to the index
+func (s *Store) indexLines(p []byte) error {
+	var records bytes.Buffer
+	for len(p) > 0 {
+		end := bytes.IndexByte(p, '\n')
+		if end < 0 {
+			end = len(p) - 1
+		}
+		if !s.indexed || s.offset-s.lastIndexed >= s.options.IndexInterval {
+			if t, ok := lineTime(p[:end+1], s.options); ok {
+				writeIndexRecord(&records, indexRecord{time: t.UnixNano(), offset: s.offset})
+				s.lastIndexed, s.indexed = s.offset, true
+			}
+		}
+		s.offset += int64(end + 1)
+		p = p[end+1:]
+	}
+	if records.Len() == 0 {
+		return nil
+	}
+	if _, err := s.index.Write(records.Bytes()); err != nil {
+		return fmt.Errorf("failed to write the jsonl index, %v", err)
+	}
+	return nil
+}
+
+// Sync commits the file and its index to stable storage
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	return s.index.Sync()
+}
+
+// Close closes the file and its index
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.file.Close()
+	if indexErr := s.index.Close(); err == nil {
+		err = indexErr
+	}
+	return err
+}
+
+// lineTime reads the time of a json line
+func lineTime(line []byte, options Options) (time.Time, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return time.Time{}, false
+	}
+	var value string
+	if err := json.Unmarshal(fields[options.TimeKey], &value); err != nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(options.TimeFormat, value)
+	return t, err == nil
+}
+
+type indexRecord struct {
+	time   int64
+	offset int64
+}
+
+func writeIndexRecord(buffer *bytes.Buffer, record indexRecord) {
+	var b [indexRecordSize]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(record.time))
+	binary.BigEndian.PutUint64(b[8:], uint64(record.offset))
+	buffer.Write(b[:])
+}
+
+func readIndexRecord(index io.ReaderAt, i int64) (indexRecord, error) {
+	var b [indexRecordSize]byte
+	if _, err := index.ReadAt(b[:], i*indexRecordSize); err != nil {
+		return indexRecord{}, err
+	}
+	return indexRecord{
+		time:   int64(binary.BigEndian.Uint64(b[:8])),
+		offset: int64(binary.BigEndian.Uint64(b[8:])),
+	}, nil
+}
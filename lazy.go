@@ -0,0 +1,36 @@
+package rogger
+
+import "fmt"
+
+// LazyMessage is a message whose arguments are only evaluated when it is
+// written, for messages which are expensive to build and logged at levels
+// usually disabled.
+//
+//	logger.Debug(rogger.LazyMsgf("state %s", func() interface{} {
+//		return dumpState()
+//	}))
+type LazyMessage struct {
+	format string
+	args   []func() interface{}
+}
+
+// LazyMsgf returns a message formatted like fmt.Sprintf, with the values
+// returned by the argument functions
+func LazyMsgf(format string, args ...func() interface{}) LazyMessage {
+	return LazyMessage{format: format, args: args}
+}
+
+// String evaluates the arguments and formats the message
+func (m LazyMessage) String() string {
+	values := make([]interface{}, len(m.args))
+	for i, arg := range m.args {
+		values[i] = arg()
+	}
+	return fmt.Sprintf(m.format, values...)
+}
+
+// MarshalText formats the message, so that it is written as text when used
+// as a param too
+func (m LazyMessage) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
@@ -0,0 +1,35 @@
+package rogger
+
+import "io"
+
+// levelOutput routes the entries from a level up to another to a writer
+type levelOutput struct {
+	min, max Level
+	out      io.Writer
+}
+
+// AddLevelOutput routes the entries from the min to the max level, both
+// included, to the writer, for example warnings and above to the standard
+// error and errors additionally to a file. Entries matching routes are
+// written to each of them instead of the logger output, which keeps the
+// entries matching none. Entries with their own output are not routed.
+func (logger *Logger) AddLevelOutput(min, max Level, out io.Writer) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.levelOutputs = append(logger.levelOutputs, levelOutput{min: min, max: max, out: out})
+}
+
+// routedOutputs returns the writers the entry is routed to by its level,
+// none when it is written to its output
+func (entry *Entry) routedOutputs() []io.Writer {
+	if entry.out != nil {
+		return nil
+	}
+	var outputs []io.Writer
+	for _, route := range entry.Logger.levelOutputs {
+		if entry.Level >= route.min && entry.Level <= route.max {
+			outputs = append(outputs, route.out)
+		}
+	}
+	return outputs
+}
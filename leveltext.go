@@ -0,0 +1,51 @@
+package rogger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LevelText customizes the level names written by a formatter, to match the
+// conventions of other logs
+type LevelText struct {
+	// Uppercase writes the names in upper case
+	Uppercase bool
+
+	// Pad follows the names with spaces up to the length of the longest
+	// one, so that what comes after them is aligned
+	Pad bool
+
+	// Names replaces the names of some levels, for example WarnLevel with
+	// "warning"
+	Names map[Level]string
+}
+
+// name returns the name of the level, as translated by the catalog unless
+// it is replaced
+func (t LevelText) name(level Level, catalog Catalog) string {
+	name, ok := t.Names[level]
+	if !ok {
+		name = catalog.translate(level.String())
+	}
+	if t.Uppercase {
+		name = strings.ToUpper(name)
+	}
+	return name
+}
+
+// padding returns the spaces following the level name
+func (t LevelText) padding(name string, catalog Catalog) string {
+	if !t.Pad {
+		return ""
+	}
+	width := 0
+	for level := Level(DebugLevel); level <= FatalLevel; level++ {
+		if w := utf8.RuneCountInString(t.name(level, catalog)); w > width {
+			width = w
+		}
+	}
+	if n := width - utf8.RuneCountInString(name); n > 0 {
+		return strings.Repeat(" ", n)
+	}
+	return ""
+}
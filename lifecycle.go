@@ -0,0 +1,99 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// lifecycle event names
+const (
+	EventProcessStart    = "process.start"
+	EventProcessSignal   = "process.signal"
+	EventProcessShutdown = "process.shutdown"
+)
+
+// Lifecycle configures the process lifecycle events logged by LogLifecycle
+type Lifecycle struct {
+	// Signals logged when received, the interrupt and termination signals
+	// by default
+	Signals []os.Signal
+
+	// KeepRunningOnSignal is for applications handling the signals
+	// themselves, such as to shut down gracefully. Otherwise the logger
+	// exits after logging a signal, with 128 plus its number as the shell
+	// does, since watching it replaces its default handling.
+	KeepRunningOnSignal bool
+}
+
+// LogLifecycle logs the start of the process as the process.start event,
+// with its arguments, build info and host, then the signals it receives as
+// process.signal events, and its shutdown as the process.shutdown event when
+// the returned function is called, typically deferred in main, so that every
+// service has the same lifecycle markers.
+//
+//	defer logger.LogLifecycle(Lifecycle{})()
+func (logger *Logger) LogLifecycle(lifecycle Lifecycle) (shutdown func()) {
+	start := time.Now()
+	logger.Event(EventProcessStart, processParams())
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	watched := lifecycle.Signals
+	if len(watched) == 0 {
+		watched = lifecycleSignals
+	}
+	signal.Notify(signals, watched...)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				logger.Event(EventProcessSignal, Params{
+					"signal": sig.String(),
+					"uptime": time.Since(start).String(),
+				})
+				if !lifecycle.KeepRunningOnSignal {
+					logger.Exit(signalExitCode(sig))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			logger.Event(EventProcessShutdown, Params{
+				"uptime": time.Since(start).String(),
+			})
+		})
+	}
+}
+
+// processParams describes the process for the start event
+func processParams() Params {
+	params := Params{
+		"pid":        os.Getpid(),
+		"args":       os.Args,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+	if host, err := os.Hostname(); err == nil {
+		params["host"] = host
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		params["module"] = info.Main.Path
+		params["version"] = info.Main.Version
+	}
+	return params
+}
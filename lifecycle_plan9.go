@@ -0,0 +1,15 @@
+//go:build plan9 && !roggerlite
+// +build plan9,!roggerlite
+
+package rogger
+
+import "os"
+
+// lifecycleSignals are the signals logged by default, plan9 only has
+// interrupts
+var lifecycleSignals = []os.Signal{os.Interrupt}
+
+// signalExitCode is the exit code of a process terminated by the signal
+func signalExitCode(sig os.Signal) int {
+	return 1
+}
@@ -0,0 +1,20 @@
+//go:build !plan9 && !roggerlite
+// +build !plan9,!roggerlite
+
+package rogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// lifecycleSignals are the signals logged by default
+var lifecycleSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// signalExitCode is the exit code of a process terminated by the signal
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
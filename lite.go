@@ -0,0 +1,28 @@
+//go:build roggerlite
+// +build roggerlite
+
+// The roggerlite build tag produces a small footprint logger for TinyGo and
+// embedded targets. It leaves out the reflection based param checks, the
+// caller lookup, the json based formatters and entry serialization, signal
+// handling and any network outputs.
+//
+//    go build -tags roggerlite
+//    tinygo build -tags roggerlite
+
+package rogger
+
+import "runtime"
+
+// isFuncParam only detects the common function types without reflection
+func isFuncParam(v interface{}) bool {
+	switch v.(type) {
+	case func(), func() error, func() string, func() interface{}:
+		return true
+	}
+	return false
+}
+
+// getCaller is not supported in the lite build, so no caller is reported
+func getCaller() *runtime.Frame {
+	return nil
+}
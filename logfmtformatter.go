@@ -0,0 +1,138 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// LogfmtFormatter formats entries as strict logfmt, with the same fields
+// and ordering as the TextFormatter, so that they can be read by logfmt
+// aware tools. Values are quoted whenever they contain spaces, equal signs,
+// quotes or control characters, with those escaped. Invalid characters are
+// removed from keys.
+type LogfmtFormatter struct {
+	// Disable timestamp logging
+	DisableTimestamp bool
+
+	// TimestampFormat to use for display when a full timestamp is printed
+	TimestampFormat string
+
+	// EpochTimestamp writes the time as a number of units since the unix
+	// epoch instead of formatting it
+	EpochTimestamp EpochUnit
+
+	// The fields are sorted by default for a consistent output.
+	DisableSorting bool
+
+	// SortingFunc orders the param keys in place instead of sorting them
+	// alphabetically, for example to put the request id first
+	SortingFunc func([]string)
+
+	// FieldMap renames the fixed keys, such as the time and message
+	FieldMap FieldMap
+
+	// LevelText customizes the level names, such as writing them in upper
+	// case
+	LevelText LevelText
+
+	// MaxMessageLength and MaxValueLength truncate the messages and param
+	// values longer than them, in bytes, noting their original length. Zero
+	// is for no maximum.
+	MaxMessageLength int
+	MaxValueLength   int
+
+	// CallerPrettyfier returns the function and file written for the caller,
+	// for example to shorten them. Empty values are not written.
+	CallerPrettyfier func(*runtime.Frame) (function, file string)
+}
+
+func (f *LogfmtFormatter) Format(entry *Entry) ([]byte, error) {
+	text := TextFormatter{
+		DisableTimestamp: f.DisableTimestamp,
+		TimestampFormat:  f.TimestampFormat,
+		EpochTimestamp:   f.EpochTimestamp,
+		DisableSorting:   f.DisableSorting,
+		SortingFunc:      f.SortingFunc,
+		FieldMap:         f.FieldMap,
+		LevelText:        f.LevelText,
+		MaxMessageLength: f.MaxMessageLength,
+		MaxValueLength:   f.MaxValueLength,
+		CallerPrettyfier: f.CallerPrettyfier,
+	}
+	return text.format(entry, appendLogfmt)
+}
+
+func appendLogfmt(buffer *bytes.Buffer, key string, value interface{}) {
+	if buffer.Len() > 0 {
+		buffer.WriteByte(' ')
+	}
+	buffer.WriteString(logfmtKey(key))
+	buffer.WriteByte('=')
+	stringVal, ok := value.(string)
+	if !ok {
+		stringVal = fmt.Sprint(value)
+	}
+	appendLogfmtValue(buffer, stringVal)
+}
+
+// logfmtKey drops the characters not allowed in logfmt keys
+func logfmtKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, key)
+	if key == "" {
+		return "_"
+	}
+	return key
+}
+
+// logfmtNeedsQuoting checks for characters which can not be in bare values
+func logfmtNeedsQuoting(value string) bool {
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
+
+func appendLogfmtValue(buffer *bytes.Buffer, value string) {
+	if !logfmtNeedsQuoting(value) {
+		buffer.WriteString(value)
+		return
+	}
+	appendLogfmtQuoted(buffer, value)
+}
+
+// appendLogfmtQuoted writes the value quoted, with the quotes, backslashes
+// and control characters escaped
+func appendLogfmtQuoted(buffer *bytes.Buffer, value string) {
+	buffer.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\', '"':
+			buffer.WriteByte('\\')
+			buffer.WriteRune(r)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < ' ' || r == 0x7f {
+				_, _ = fmt.Fprintf(buffer, `\u%04x`, r)
+			} else {
+				// invalid utf-8 is written as the replacement character
+				buffer.WriteRune(r)
+			}
+		}
+	}
+	buffer.WriteByte('"')
+}
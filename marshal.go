@@ -0,0 +1,100 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// jsonEntry is the serialized form of an entry
+type jsonEntry struct {
+	Time    time.Time                  `json:"time"`
+	Level   string                     `json:"level"`
+	Message string                     `json:"message,omitempty"`
+	Event   string                     `json:"event,omitempty"`
+	Params  map[string]json.RawMessage `json:"params,omitempty"`
+	Error   string                     `json:"error,omitempty"`
+	Caller  *jsonCaller                `json:"caller,omitempty"`
+}
+
+type jsonCaller struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// MarshalJSON serializes the entry so that it can be shipped to another
+// process and restored with UnmarshalEntry. Params which can not be encoded
+// as json, including errors, are stored as their string representation.
+func (entry *Entry) MarshalJSON() ([]byte, error) {
+	e := jsonEntry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Event:   entry.EventName,
+		Error:   entry.err,
+	}
+	if len(entry.Data) > 0 {
+		e.Params = make(map[string]json.RawMessage, len(entry.Data))
+		for k, v := range entry.Data {
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			raw, err := json.Marshal(v)
+			if err != nil {
+				raw, _ = json.Marshal(fmt.Sprint(v))
+			}
+			e.Params[k] = raw
+		}
+	}
+	if caller := entry.GetCaller(); caller != nil {
+		e.Caller = &jsonCaller{
+			Function: caller.Function,
+			File:     caller.File,
+			Line:     caller.Line,
+		}
+	}
+	return json.Marshal(e)
+}
+
+// UnmarshalEntry restores an entry serialized with MarshalJSON and attaches
+// it to the logger, so it can be written again using Logger.Replay.
+// Numeric params are kept as json.Number to avoid losing precision.
+func UnmarshalEntry(data []byte, logger *Logger) (*Entry, error) {
+	var e jsonEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	level, err := ParseLevel(e.Level)
+	if err != nil {
+		return nil, err
+	}
+	entry := NewEntry(logger)
+	entry.Time = e.Time
+	entry.Level = level
+	entry.Message = e.Message
+	entry.EventName = e.Event
+	entry.err = e.Error
+	for k, raw := range e.Params {
+		var v interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+		entry.Data[k] = v
+	}
+	if e.Caller != nil {
+		entry.Caller = &runtime.Frame{
+			Function: e.Caller.Function,
+			File:     e.Caller.File,
+			Line:     e.Caller.Line,
+		}
+	}
+	return entry, nil
+}
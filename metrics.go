@@ -0,0 +1,187 @@
+package rogger
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricRule turns the entries matching it into a metric, either counting
+// them or observing one of their params. All the conditions set must match.
+type MetricRule struct {
+	// Name of the metric in Stats
+	Name string
+
+	// MinLevel entries must be logged at
+	MinLevel Level
+
+	// MessageContains matches entries with the text in their message
+	MessageContains string
+
+	// Params the entry must have, with equal values, a nil value matches
+	// any value
+	Params Params
+
+	// Match is an additional predicate for the entry
+	Match func(*Entry) bool
+
+	// ObserveParam is the numeric param observed into a histogram, entries
+	// are only counted when it is empty. Durations are observed in seconds.
+	ObserveParam string
+
+	// Buckets are the upper bounds of the histogram buckets
+	Buckets []float64
+}
+
+// MetricStats is a snapshot of a metric
+type MetricStats struct {
+	// number of entries matched, or values observed
+	Count uint64 `json:"count"`
+
+	// sum, min and max of the values observed
+	Sum float64 `json:"sum"`
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+
+	// number of values observed in each bucket
+	Buckets []BucketCount `json:"buckets,omitempty"`
+}
+
+// BucketCount is the number of values observed up to the upper bound
+type BucketCount struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+// metric is a rule with its state
+type metric struct {
+	rule  MetricRule
+	stats MetricStats
+}
+
+// metricRules holds the metrics of a logger
+type metricRules struct {
+	mu      sync.Mutex
+	metrics []*metric
+}
+
+// AddMetricRule adds a rule turning log entries into a metric, reported in
+// the Metrics of Stats
+func (logger *Logger) AddMetricRule(rule MetricRule) {
+	m := &metric{rule: rule}
+	for _, bound := range rule.Buckets {
+		m.stats.Buckets = append(m.stats.Buckets, BucketCount{UpperBound: bound})
+	}
+	logger.metrics.mu.Lock()
+	defer logger.metrics.mu.Unlock()
+	logger.metrics.metrics = append(logger.metrics.metrics, m)
+}
+
+// observe updates the metrics matching the entry
+func (r *metricRules) observe(entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.metrics {
+		if !m.rule.matches(entry) {
+			continue
+		}
+		if m.rule.ObserveParam == "" {
+			m.stats.Count++
+			continue
+		}
+		value, ok := numericParam(entry.Data[m.rule.ObserveParam])
+		if !ok {
+			continue
+		}
+		if m.stats.Count == 0 || value < m.stats.Min {
+			m.stats.Min = value
+		}
+		if m.stats.Count == 0 || value > m.stats.Max {
+			m.stats.Max = value
+		}
+		m.stats.Count++
+		m.stats.Sum += value
+		for i := range m.stats.Buckets {
+			if value <= m.stats.Buckets[i].UpperBound {
+				m.stats.Buckets[i].Count++
+			}
+		}
+	}
+}
+
+func (r *metricRules) snapshot() map[string]MetricStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.metrics) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]MetricStats, len(r.metrics))
+	for _, m := range r.metrics {
+		stats := m.stats
+		stats.Buckets = append([]BucketCount(nil), m.stats.Buckets...)
+		snapshot[m.rule.Name] = stats
+	}
+	return snapshot
+}
+
+func (rule *MetricRule) matches(entry *Entry) bool {
+	if entry.Level < rule.MinLevel {
+		return false
+	}
+	if rule.MessageContains != "" && !strings.Contains(entry.Message, rule.MessageContains) {
+		return false
+	}
+	for k, expected := range rule.Params {
+		v, ok := entry.Data[k]
+		if !ok || (expected != nil && !paramEquals(v, expected)) {
+			return false
+		}
+	}
+	return rule.Match == nil || rule.Match(entry)
+}
+
+// paramEquals compares param values, without panicking on uncomparable ones
+func paramEquals(a, b interface{}) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	return a == b
+}
+
+// numericParam converts a numeric param value to a float
+func numericParam(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Duration:
+		return v.Seconds(), true
+	case decodedNumber:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+// Package mock provides a logger which records the logged entries instead
+// of writing them, for asserting on logs in unit tests.
+//
+//	logger, recorder := mock.NewLogger()
+//	service := NewService(logger)
+//	service.Run()
+//	if last := recorder.LastCall(); last == nil || last.Level != rogger.ErrorLevel {
+//		t.Error("expected an error to be logged")
+//	}
+package mock
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// Call is a single logged entry
+type Call struct {
+	Level   rogger.Level
+	Message string
+	Event   string
+	Params  rogger.Params
+	Time    time.Time
+}
+
+// Recorder records the entries logged through a logger. It is used as the
+// formatter of the logger, so it sees every entry which is written.
+type Recorder struct {
+	mu       sync.Mutex
+	calls    []Call
+	exitCode int
+	exited   bool
+}
+
+// NewLogger creates a logger at debug level which records every entry to the
+// returned recorder and writes nothing. Fatal logs are recorded as an exit
+// instead of exiting the application.
+func NewLogger() (*rogger.Logger, *Recorder) {
+	recorder := new(Recorder)
+	logger := rogger.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.SetFormatter(recorder)
+	logger.SetLevel(rogger.DebugLevel)
+	logger.ExitFunc = recorder.exit
+	return logger, recorder
+}
+
+// Format records the entry
+func (r *Recorder) Format(entry *rogger.Entry) ([]byte, error) {
+	params := make(rogger.Params, len(entry.Data))
+	for k, v := range entry.Data {
+		params[k] = v
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Event:   entry.EventName,
+		Params:  params,
+		Time:    entry.Time,
+	})
+	return nil, nil
+}
+
+func (r *Recorder) exit(code int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exitCode = code
+	r.exited = true
+}
+
+// Calls returns all the recorded entries in order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallsAt returns the recorded entries logged at the level
+func (r *Recorder) CallsAt(level rogger.Level) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Level == level {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// LastCall returns the last recorded entry, nil if there is none
+func (r *Recorder) LastCall() *Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) == 0 {
+		return nil
+	}
+	call := r.calls[len(r.calls)-1]
+	return &call
+}
+
+// Len returns the number of recorded entries
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+// Exited returns the exit code if a fatal entry was logged
+func (r *Recorder) Exited() (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exitCode, r.exited
+}
+
+// Reset removes all the recorded entries and exits
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.exited = false
+	r.exitCode = 0
+}
@@ -0,0 +1,243 @@
+package rogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// MsgpackFormatter formats entries as MessagePack maps, which are cheaper to
+// encode and smaller than json for high volume logging. The time is encoded
+// with the msgpack timestamp extension, the other fixed keys and params the
+// same way as the TextFormatter. Entries are not delimited, as msgpack
+// values can be read back to back.
+type MsgpackFormatter struct {
+	// Disable timestamp logging
+	DisableTimestamp bool
+
+	// EpochTimestamp writes the time as a number of units since the unix
+	// epoch instead of formatting it
+	EpochTimestamp EpochUnit
+
+	// FieldMap renames the fixed keys, such as the time and message
+	FieldMap FieldMap
+
+	// DataKey nests the params under the key when set, so that they never
+	// clash with the fixed keys, which are then not renamed
+	DataKey string
+
+	// CallerPrettyfier returns the function and file written for the caller,
+	// for example to shorten them. Empty values are not written.
+	CallerPrettyfier func(*runtime.Frame) (function, file string)
+}
+
+func (f *MsgpackFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Params, len(entry.Data)+7)
+	hasCaller := entry.HasCaller()
+	if f.DataKey != "" {
+		if len(entry.Data) > 0 {
+			data[f.DataKey] = entry.Data
+		}
+	} else {
+		for k, v := range entry.Data {
+			data[k] = v
+		}
+		f.FieldMap.fixParamsClash(data, entry)
+	}
+	if !f.DisableTimestamp {
+		if f.EpochTimestamp != EpochNone {
+			data[f.FieldMap.resolve(timeKey)] = f.EpochTimestamp.epoch(entry.Time)
+		} else {
+			data[f.FieldMap.resolve(timeKey)] = entry.Time
+		}
+	}
+	if entry.Message != "" {
+		data[f.FieldMap.resolve(msgKey)] = entry.Message
+	}
+	if entry.EventName != "" {
+		data[f.FieldMap.resolve(eventKey)] = entry.EventName
+	}
+	data[f.FieldMap.resolve(levelKey)] = entry.Level.String()
+	if entry.err != "" {
+		data[f.FieldMap.resolve(errKey)] = entry.err
+	}
+	if hasCaller {
+		function, file := callerFields(entry, f.CallerPrettyfier)
+		if function != "" {
+			data[f.FieldMap.resolve(funcKey)] = function
+		}
+		if file != "" {
+			data[f.FieldMap.resolve(fileKey)] = file
+		}
+	}
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	appendMsgpack(buffer, data)
+	return buffer.Bytes(), nil
+}
+
+// appendMsgpack encodes the value, values of types with no msgpack
+// equivalent are encoded as their string representation
+func appendMsgpack(buffer *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteByte(0xc0)
+	case bool:
+		if v {
+			buffer.WriteByte(0xc3)
+		} else {
+			buffer.WriteByte(0xc2)
+		}
+	case int:
+		appendMsgpackInt(buffer, int64(v))
+	case int8:
+		appendMsgpackInt(buffer, int64(v))
+	case int16:
+		appendMsgpackInt(buffer, int64(v))
+	case int32:
+		appendMsgpackInt(buffer, int64(v))
+	case int64:
+		appendMsgpackInt(buffer, v)
+	case uint:
+		appendMsgpackUint(buffer, uint64(v))
+	case uint8:
+		appendMsgpackUint(buffer, uint64(v))
+	case uint16:
+		appendMsgpackUint(buffer, uint64(v))
+	case uint32:
+		appendMsgpackUint(buffer, uint64(v))
+	case uint64:
+		appendMsgpackUint(buffer, v)
+	case float32:
+		buffer.WriteByte(0xca)
+		_ = binary.Write(buffer, binary.BigEndian, math.Float32bits(v))
+	case float64:
+		buffer.WriteByte(0xcb)
+		_ = binary.Write(buffer, binary.BigEndian, math.Float64bits(v))
+	case decodedNumber:
+		if i, err := v.Int64(); err == nil {
+			appendMsgpackInt(buffer, i)
+		} else if f, err := v.Float64(); err == nil {
+			appendMsgpack(buffer, f)
+		} else {
+			appendMsgpackString(buffer, v.String())
+		}
+	case string:
+		appendMsgpackString(buffer, v)
+	case []byte:
+		appendMsgpackHeader(buffer, len(v), 0, 0xc4, 0xc5, 0xc6)
+		buffer.Write(v)
+	case time.Time:
+		// timestamp 96 extension
+		buffer.WriteByte(0xc7)
+		buffer.WriteByte(12)
+		buffer.WriteByte(0xff)
+		_ = binary.Write(buffer, binary.BigEndian, uint32(v.Nanosecond()))
+		_ = binary.Write(buffer, binary.BigEndian, v.Unix())
+	case time.Duration:
+		appendMsgpackString(buffer, v.String())
+	case error:
+		appendMsgpackString(buffer, v.Error())
+	case []interface{}:
+		appendMsgpackHeader(buffer, len(v), 0x90, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			appendMsgpack(buffer, item)
+		}
+	case []string:
+		appendMsgpackHeader(buffer, len(v), 0x90, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			appendMsgpackString(buffer, item)
+		}
+	case Params:
+		appendMsgpackMap(buffer, v)
+	case map[string]interface{}:
+		appendMsgpackMap(buffer, v)
+	default:
+		appendMsgpackString(buffer, fmt.Sprint(v))
+	}
+}
+
+func appendMsgpackMap(buffer *bytes.Buffer, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	appendMsgpackHeader(buffer, len(m), 0x80, 0, 0xde, 0xdf)
+	for _, k := range keys {
+		appendMsgpackString(buffer, k)
+		appendMsgpack(buffer, m[k])
+	}
+}
+
+func appendMsgpackString(buffer *bytes.Buffer, s string) {
+	appendMsgpackHeader(buffer, len(s), 0xa0, 0xd9, 0xda, 0xdb)
+	buffer.WriteString(s)
+}
+
+// appendMsgpackHeader writes the type and length of a string, binary, array
+// or map, using the fixed type when the length allows it. Zero is passed for
+// the types which do not exist for the kind.
+func appendMsgpackHeader(buffer *bytes.Buffer, n int, fixed, b8, b16, b32 byte) {
+	switch {
+	case fixed != 0 && fixed != 0xa0 && n < 16:
+		buffer.WriteByte(fixed | byte(n))
+	case fixed == 0xa0 && n < 32:
+		buffer.WriteByte(fixed | byte(n))
+	case b8 != 0 && n <= math.MaxUint8:
+		buffer.WriteByte(b8)
+		buffer.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buffer.WriteByte(b16)
+		_ = binary.Write(buffer, binary.BigEndian, uint16(n))
+	default:
+		buffer.WriteByte(b32)
+		_ = binary.Write(buffer, binary.BigEndian, uint32(n))
+	}
+}
+
+func appendMsgpackInt(buffer *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0:
+		appendMsgpackUint(buffer, uint64(i))
+	case i >= -32:
+		buffer.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		buffer.WriteByte(0xd0)
+		buffer.WriteByte(byte(i))
+	case i >= math.MinInt16:
+		buffer.WriteByte(0xd1)
+		_ = binary.Write(buffer, binary.BigEndian, int16(i))
+	case i >= math.MinInt32:
+		buffer.WriteByte(0xd2)
+		_ = binary.Write(buffer, binary.BigEndian, int32(i))
+	default:
+		buffer.WriteByte(0xd3)
+		_ = binary.Write(buffer, binary.BigEndian, i)
+	}
+}
+
+func appendMsgpackUint(buffer *bytes.Buffer, u uint64) {
+	switch {
+	case u <= 127:
+		buffer.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		buffer.WriteByte(0xcc)
+		buffer.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		buffer.WriteByte(0xcd)
+		_ = binary.Write(buffer, binary.BigEndian, uint16(u))
+	case u <= math.MaxUint32:
+		buffer.WriteByte(0xce)
+		_ = binary.Write(buffer, binary.BigEndian, uint32(u))
+	default:
+		buffer.WriteByte(0xcf)
+		_ = binary.Write(buffer, binary.BigEndian, u)
+	}
+}
@@ -0,0 +1,91 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Destination is an output of a MultiOutput
+type Destination struct {
+	// Out the entries are written to
+	Out io.Writer
+
+	// MinLevel of the entries written to it
+	MinLevel Level
+
+	// Formatter of the entries, the logger formatter is used when it is nil
+	Formatter Formatter
+}
+
+// MultiOutput writes the entries to several destinations, each with its own
+// level and formatter, for example readable text to the terminal and json to
+// a file. Set as the logger output, the entries are formatted again for the
+// destinations with their own formatter, and the write timeout applies to
+// each destination. Written to directly, such as by LogBatch, the entries are
+// written as they are formatted to all the destinations of their level.
+type MultiOutput struct {
+	Destinations []Destination
+}
+
+// NewMultiOutput returns an output writing to the destinations
+func NewMultiOutput(destinations ...Destination) *MultiOutput {
+	return &MultiOutput{Destinations: destinations}
+}
+
+func (m *MultiOutput) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, d := range m.Destinations {
+		if _, err := d.Out.Write(p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(p), firstErr
+}
+
+func (m *MultiOutput) WriteLevel(level Level, p []byte) (int, error) {
+	var firstErr error
+	for _, d := range m.Destinations {
+		if level < d.MinLevel {
+			continue
+		}
+		if _, err := writeLevel(d.Out, level, p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(p), firstErr
+}
+
+// entryWriter is implemented by the outputs writing the entries themselves
+// instead of the formatted bytes, which are then written through writeTo
+type entryWriter interface {
+	writeEntry(entry *Entry, formattedLog []byte)
+}
+
+// writeEntry writes the entry to the destinations of its level, formatting
+// it again for the ones with their own formatter
+func (m *MultiOutput) writeEntry(entry *Entry, formattedLog []byte) {
+	for _, d := range m.Destinations {
+		if entry.Level < d.MinLevel {
+			continue
+		}
+		if d.Formatter == nil {
+			entry.writeTo(d.Out, formattedLog)
+			continue
+		}
+		// the copy is formatted into its own buffer as the entry one holds
+		// the formatted log, and is written to the destination so that the
+		// formatter checks it for colors
+		formatted := *entry
+		formatted.out = d.Out
+		formatted.Buffer = bufferPool.Get().(*bytes.Buffer)
+		formatted.Buffer.Reset()
+		p, err := d.Formatter.Format(&formatted)
+		if err != nil {
+			entry.Logger.handleError(ErrorFormat, fmt.Errorf(entry.Logger.Catalog.translate(CatalogFormatFailed), err))
+		} else {
+			entry.writeTo(d.Out, p)
+		}
+		bufferPool.Put(formatted.Buffer)
+	}
+}
@@ -0,0 +1,207 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// network writer defaults
+const (
+	defaultMinBackoff  = 100 * time.Millisecond
+	defaultMaxBackoff  = 30 * time.Second
+	defaultDialTimeout = 5 * time.Second
+	defaultNetBuffer   = 1 << 20
+)
+
+// NetWriter streams the entries to a tcp or udp endpoint. When the
+// connection fails, it reconnects in the background with an exponential
+// backoff, buffering the entries written in the meantime up to a limit and
+// dropping the ones beyond it, so that logging never waits on an outage. It
+// is safe for concurrent use.
+type NetWriter struct {
+	// Network and Address of the endpoint, such as tcp and
+	// logs.example.com:5170
+	Network string
+	Address string
+
+	// MinBackoff and MaxBackoff bound the delay between reconnections, which
+	// doubles on every failure, 100 milliseconds and 30 seconds by default
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// DialTimeout bounds the connections and every write to them, 5 seconds
+	// by default, so that a stalled endpoint is reconnected to
+	DialTimeout time.Duration
+
+	// BufferSize is the number of bytes buffered while disconnected, 1 MiB by
+	// default
+	BufferSize int
+
+	mu           sync.Mutex
+	conn         net.Conn
+	pending      [][]byte
+	pendingBytes int
+	dropped      uint64
+	reconnecting bool
+	closed       bool
+	stop         chan struct{}
+}
+
+// Write writes the bytes to the connection, or buffers them while it is
+// being made, along with the part of a write the connection failed on. It
+// only fails once the writer is closed.
+func (w *NetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.conn == nil && !w.reconnecting {
+		// the first connection is made in the background like the others
+		w.reconnect()
+	}
+	if w.conn != nil {
+		n, err := w.writeConn(w.conn, p)
+		if err == nil {
+			return len(p), nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+		w.reconnect()
+		w.buffer(p[n:])
+		return len(p), nil
+	}
+	w.buffer(p)
+	return len(p), nil
+}
+
+// Dropped returns the number of writes dropped as the buffer was full
+func (w *NetWriter) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// Close closes the connection and stops reconnecting, the buffered writes
+// are dropped
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.stop != nil {
+		close(w.stop)
+	}
+	w.pending, w.pendingBytes = nil, 0
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// buffer keeps a copy of the write for the connection, it must be called
+// with the lock held
+func (w *NetWriter) buffer(p []byte) {
+	size := w.BufferSize
+	if size <= 0 {
+		size = defaultNetBuffer
+	}
+	if w.pendingBytes+len(p) > size {
+		w.dropped++
+		return
+	}
+	w.pending = append(w.pending, append([]byte(nil), p...))
+	w.pendingBytes += len(p)
+}
+
+// reconnect starts connecting in the background, it must be called with the
+// lock held
+func (w *NetWriter) reconnect() {
+	w.reconnecting = true
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+	}
+	go w.connect(w.stop)
+}
+
+// connect dials until it succeeds, and writes the buffered entries before
+// the new ones
+func (w *NetWriter) connect(stop chan struct{}) {
+	backoff := w.MinBackoff
+	if backoff <= 0 {
+		backoff = defaultMinBackoff
+	}
+	maxBackoff := w.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	for {
+		conn, err := net.DialTimeout(w.Network, w.Address, w.dialTimeout())
+		if err == nil {
+			w.mu.Lock()
+			if w.closed {
+				w.mu.Unlock()
+				_ = conn.Close()
+				return
+			}
+			if w.flushPending(conn) {
+				w.conn = conn
+				w.reconnecting = false
+				w.mu.Unlock()
+				return
+			}
+			w.mu.Unlock()
+			_ = conn.Close()
+		}
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			return
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// flushPending writes the buffered entries to the connection, keeping the
+// part left when it fails, it must be called with the lock held
+func (w *NetWriter) flushPending(conn net.Conn) bool {
+	for len(w.pending) > 0 {
+		if n, err := w.writeConn(conn, w.pending[0]); err != nil {
+			w.pending[0] = w.pending[0][n:]
+			w.pendingBytes -= n
+			return false
+		}
+		w.pendingBytes -= len(w.pending[0])
+		w.pending = w.pending[1:]
+	}
+	w.pending = nil
+	return true
+}
+
+// writeConn writes the bytes to the connection within the dial timeout,
+// returning the number written before it failed
+func (w *NetWriter) writeConn(conn net.Conn, p []byte) (int, error) {
+	if err := conn.SetWriteDeadline(time.Now().Add(w.dialTimeout())); err != nil {
+		return 0, err
+	}
+	return conn.Write(p)
+}
+
+func (w *NetWriter) dialTimeout() time.Duration {
+	if w.DialTimeout <= 0 {
+		return defaultDialTimeout
+	}
+	return w.DialTimeout
+}
@@ -0,0 +1,15 @@
+//go:build !roggerdebug
+// +build !roggerdebug
+
+package rogger
+
+// entryDebug holds nothing without the roggerdebug build tag
+type entryDebug struct{}
+
+func (entry *Entry) debugAcquire() {}
+
+func (entry *Entry) debugRelease() {}
+
+func (entry *Entry) debugSeal() {}
+
+func (entry *Entry) debugCheck() {}
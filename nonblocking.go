@@ -0,0 +1,144 @@
+package rogger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// default number of writes a NonBlockingWriter buffers
+const defaultNonBlockingSize = 1024
+
+// DropPolicy is what a NonBlockingWriter does with writes when it is full
+type DropPolicy int
+
+// Drop policies
+const (
+	// DropNewest drops the write made while full
+	DropNewest DropPolicy = iota
+
+	// DropOldest drops the oldest buffered write to make room for it
+	DropOldest
+
+	// BlockWhenFull waits for room, dropping nothing
+	BlockWhenFull
+)
+
+// NonBlockingWriter buffers the writes and writes them to the output in the
+// background, so that latency critical callers never wait on it. What
+// happens when the buffer is full is up to the drop policy, and the writes
+// dropped are counted. It is safe for concurrent use.
+type NonBlockingWriter struct {
+	// Out written to in the background
+	Out io.Writer
+
+	// Size of the buffer in writes, 1024 by default
+	Size int
+
+	// Policy when the buffer is full, DropNewest by default
+	Policy DropPolicy
+
+	// OnError is called with the errors of the output, which are otherwise
+	// ignored as the writes have returned already
+	OnError func(error)
+
+	startOnce sync.Once
+	mu        sync.Mutex
+	queue     chan pendingWrite
+	done      chan struct{}
+	closed    bool
+	dropped   uint64
+}
+
+func (w *NonBlockingWriter) Write(p []byte) (int, error) {
+	return w.enqueue(pendingWrite{out: w.Out, p: append([]byte(nil), p...)})
+}
+
+func (w *NonBlockingWriter) WriteLevel(level Level, p []byte) (int, error) {
+	return w.enqueue(pendingWrite{out: levelOut{w.Out, level}, p: append([]byte(nil), p...)})
+}
+
+// Dropped returns the number of writes dropped as the buffer was full
+func (w *NonBlockingWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close writes the buffered writes and stops the background writer, the
+// writes made afterwards fail
+func (w *NonBlockingWriter) Close() error {
+	w.start()
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+	<-w.done
+	return nil
+}
+
+func (w *NonBlockingWriter) start() {
+	w.startOnce.Do(func() {
+		size := w.Size
+		if size <= 0 {
+			size = defaultNonBlockingSize
+		}
+		w.queue = make(chan pendingWrite, size)
+		w.done = make(chan struct{})
+		go w.drain()
+	})
+}
+
+// enqueue buffers the write following the drop policy
+func (w *NonBlockingWriter) enqueue(pw pendingWrite) (int, error) {
+	w.start()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	select {
+	case w.queue <- pw:
+		return len(pw.p), nil
+	default:
+	}
+	switch w.Policy {
+	case DropOldest:
+		// the background writer may take the oldest one in the meantime,
+		// in which case there is room without dropping
+		select {
+		case <-w.queue:
+			atomic.AddUint64(&w.dropped, 1)
+		default:
+		}
+		w.queue <- pw
+	case BlockWhenFull:
+		w.queue <- pw
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(pw.p), nil
+}
+
+// drain writes the buffered writes until the writer is closed
+func (w *NonBlockingWriter) drain() {
+	defer close(w.done)
+	for pw := range w.queue {
+		if _, err := pw.out.Write(pw.p); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+	}
+}
+
+// levelOut writes to the output with the level of the write
+type levelOut struct {
+	out   io.Writer
+	level Level
+}
+
+func (o levelOut) Write(p []byte) (int, error) {
+	return writeLevel(o.out, o.level, p)
+}
@@ -0,0 +1,40 @@
+package rogger
+
+import "sync/atomic"
+
+// NopFormatter formats every entry to nothing. Together with CountingWriter
+// it helps benchmark building entries apart from formatting and writing.
+type NopFormatter struct{}
+
+func (NopFormatter) Format(*Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// CountingWriter discards everything written to it, counting the writes and
+// the bytes. It is safe for concurrent use.
+type CountingWriter struct {
+	writes uint64
+	bytes  uint64
+}
+
+func (w *CountingWriter) Write(p []byte) (int, error) {
+	atomic.AddUint64(&w.writes, 1)
+	atomic.AddUint64(&w.bytes, uint64(len(p)))
+	return len(p), nil
+}
+
+// Writes returns the number of writes
+func (w *CountingWriter) Writes() uint64 {
+	return atomic.LoadUint64(&w.writes)
+}
+
+// Bytes returns the number of bytes written
+func (w *CountingWriter) Bytes() uint64 {
+	return atomic.LoadUint64(&w.bytes)
+}
+
+// Reset sets the counts back to zero
+func (w *CountingWriter) Reset() {
+	atomic.StoreUint64(&w.writes, 0)
+	atomic.StoreUint64(&w.bytes, 0)
+}
@@ -0,0 +1,230 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// default keys of the trace context params
+const (
+	defaultTraceIDKey = "trace_id"
+	defaultSpanIDKey  = "span_id"
+)
+
+// OTLPFormatter formats entries as OpenTelemetry log records in the OTLP
+// json encoding, one per line, so that they can be handed to an OTLP
+// exporter or collector as they are. The message is the body, and the
+// params are the attributes, along with the event, error and caller named
+// after the semantic conventions.
+type OTLPFormatter struct {
+	// Severities the levels are mapped with, defaults to OTLPSeverities
+	Severities SeverityProfile
+
+	// TraceIDKey and SpanIDKey are the params holding the hex trace and
+	// span ids, trace_id and span_id by default. They are written as the
+	// ids of the record instead of attributes.
+	TraceIDKey string
+	SpanIDKey  string
+
+	// TraceContext returns the hex trace and span ids of the entry context,
+	// for example from the active span, when the params have none
+	TraceContext func(ctx context.Context) (traceID, spanID string)
+}
+
+// otlpRecord is a log record of the OTLP json encoding
+type otlpRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber"`
+	SeverityText         string          `json:"severityText"`
+	Body                 *otlpValue      `json:"body,omitempty"`
+	Attributes           []otlpAttribute `json:"attributes,omitempty"`
+	TraceID              string          `json:"traceId,omitempty"`
+	SpanID               string          `json:"spanId,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is an AnyValue, with a single of its fields set
+type otlpValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *string        `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArray     `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValues `json:"kvlistValue,omitempty"`
+}
+
+type otlpArray struct {
+	Values []otlpValue `json:"values"`
+}
+
+type otlpKeyValues struct {
+	Values []otlpAttribute `json:"values"`
+}
+
+func (f *OTLPFormatter) Format(entry *Entry) ([]byte, error) {
+	severity := f.Severities.orDefault(OTLPSeverities).Severity(entry.Level)
+	record := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       severity.Number,
+		SeverityText:         severity.Name,
+	}
+	if entry.Message != "" {
+		body := otlpString(entry.Message)
+		record.Body = &body
+	}
+
+	traceIDKey := firstNonEmpty(f.TraceIDKey, defaultTraceIDKey)
+	spanIDKey := firstNonEmpty(f.SpanIDKey, defaultSpanIDKey)
+	keys := make([]string, 0, len(entry.Data))
+	for k, v := range entry.Data {
+		switch k {
+		case traceIDKey:
+			record.TraceID = fmt.Sprint(v)
+		case spanIDKey:
+			record.SpanID = fmt.Sprint(v)
+		default:
+			keys = append(keys, k)
+		}
+	}
+	if record.TraceID == "" && f.TraceContext != nil && entry.Context != nil {
+		record.TraceID, record.SpanID = f.TraceContext(entry.Context)
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		record.Attributes = append(record.Attributes, otlpAttribute{Key: k, Value: otlpAnyValue(entry.Data[k])})
+	}
+	if entry.EventName != "" {
+		record.Attributes = append(record.Attributes, otlpAttribute{Key: "event.name", Value: otlpString(entry.EventName)})
+	}
+	if entry.err != "" {
+		record.Attributes = append(record.Attributes, otlpAttribute{Key: "exception.message", Value: otlpString(entry.err)})
+	}
+	if entry.HasCaller() {
+		caller := entry.GetCaller()
+		record.Attributes = append(record.Attributes,
+			otlpAttribute{Key: "code.function", Value: otlpString(caller.Function)},
+			otlpAttribute{Key: "code.filepath", Value: otlpString(caller.File)},
+			otlpAttribute{Key: "code.lineno", Value: otlpInt(int64(caller.Line))},
+		)
+	}
+
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	if err := json.NewEncoder(buffer).Encode(record); err != nil {
+		return nil, fmt.Errorf("failed to marshal otlp log record, %v", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func otlpString(s string) otlpValue {
+	return otlpValue{StringValue: &s}
+}
+
+// otlpAnyValue converts a param value, values of types with no equivalent
+// are written as their string representation
+func otlpAnyValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case nil:
+		return otlpValue{}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int:
+		return otlpInt(int64(v))
+	case int8:
+		return otlpInt(int64(v))
+	case int16:
+		return otlpInt(int64(v))
+	case int32:
+		return otlpInt(int64(v))
+	case int64:
+		return otlpInt(v)
+	case uint:
+		return otlpUint(uint64(v))
+	case uint8:
+		return otlpUint(uint64(v))
+	case uint16:
+		return otlpUint(uint64(v))
+	case uint32:
+		return otlpUint(uint64(v))
+	case uint64:
+		return otlpUint(v)
+	case float32:
+		d := float64(v)
+		return otlpValue{DoubleValue: &d}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	case decodedNumber:
+		if i, err := v.Int64(); err == nil {
+			return otlpInt(i)
+		}
+		if d, err := v.Float64(); err == nil {
+			return otlpValue{DoubleValue: &d}
+		}
+		return otlpString(v.String())
+	case string:
+		return otlpString(v)
+	case time.Time:
+		return otlpString(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otlpString(v.String())
+	case error:
+		return otlpString(v.Error())
+	case []interface{}:
+		values := make([]otlpValue, len(v))
+		for i, item := range v {
+			values[i] = otlpAnyValue(item)
+		}
+		return otlpValue{ArrayValue: &otlpArray{Values: values}}
+	case []string:
+		values := make([]otlpValue, len(v))
+		for i, item := range v {
+			values[i] = otlpString(item)
+		}
+		return otlpValue{ArrayValue: &otlpArray{Values: values}}
+	case Params:
+		return otlpKvlist(v)
+	case map[string]interface{}:
+		return otlpKvlist(v)
+	}
+	return otlpString(fmt.Sprint(value))
+}
+
+func otlpInt(i int64) otlpValue {
+	s := strconv.FormatInt(i, 10)
+	return otlpValue{IntValue: &s}
+}
+
+func otlpUint(u uint64) otlpValue {
+	s := strconv.FormatUint(u, 10)
+	return otlpValue{IntValue: &s}
+}
+
+func otlpKvlist(m map[string]interface{}) otlpValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]otlpAttribute, len(keys))
+	for i, k := range keys {
+		values[i] = otlpAttribute{Key: k, Value: otlpAnyValue(m[k])}
+	}
+	return otlpValue{KvlistValue: &otlpKeyValues{Values: values}}
+}
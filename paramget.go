@@ -0,0 +1,107 @@
+package rogger
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// decodedNumber is a number kept as text when decoded, such as the
+// json.Number params of replayed entries. It is matched by its methods so
+// that the lite build does not need encoding/json.
+type decodedNumber interface {
+	Int64() (int64, error)
+	Float64() (float64, error)
+	String() string
+}
+
+// Param returns the value of a param and whether the entry has it
+func (entry *Entry) Param(key string) (interface{}, bool) {
+	v, ok := entry.Data[key]
+	return v, ok
+}
+
+// ParamString returns a string param, or the text of a fmt.Stringer. It
+// is false if the param is missing or of another type.
+func (entry *Entry) ParamString(key string) (string, bool) {
+	switch v := entry.Data[key].(type) {
+	case string:
+		return v, true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// ParamInt returns an integer param of any size, including numbers of
+// replayed entries. It is false if the param is missing, of another type
+// or does not fit.
+func (entry *Entry) ParamInt(key string) (int64, bool) {
+	switch v := entry.Data[key].(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case decodedNumber:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// ParamFloat returns a numeric param as a float, with durations in seconds
+// like the metric rules. It is false if the param is missing or not a
+// number.
+func (entry *Entry) ParamFloat(key string) (float64, bool) {
+	return numericParam(entry.Data[key])
+}
+
+// ParamBool returns a boolean param. It is false if the param is missing or
+// of another type.
+func (entry *Entry) ParamBool(key string) (value bool, ok bool) {
+	value, ok = entry.Data[key].(bool)
+	return
+}
+
+// ParamTime returns a time param, or a string param in RFC 3339 like the
+// times of replayed entries. It is false if the param is missing or of
+// another type.
+func (entry *Entry) ParamTime(key string) (time.Time, bool) {
+	switch v := entry.Data[key].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// ParamDuration returns a duration param, or a string param like "1.5s". It
+// is false if the param is missing or of another type.
+func (entry *Entry) ParamDuration(key string) (time.Duration, bool) {
+	switch v := entry.Data[key].(type) {
+	case time.Duration:
+		return v, true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,20 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import "reflect"
+
+// isFuncParam checks whether the param value is a function or a pointer to
+// one, which cannot be logged
+func isFuncParam(v interface{}) bool {
+	if t := reflect.TypeOf(v); t != nil {
+		switch t.Kind() {
+		case reflect.Func:
+			return true
+		case reflect.Ptr:
+			return t.Elem().Kind() == reflect.Func
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// Package parse reads lines written by the rogger text formatter back into
+// structured entries, for log processing tools and tests asserting on
+// emitted output.
+package parse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sinhashubham95/rogger"
+)
+
+// keys written by the text formatter
+const (
+	timeKey      = "time"
+	msgKey       = "message"
+	levelKey     = "level"
+	errKey       = "error"
+	funcKey      = "func"
+	fileKey      = "file"
+	eventKey     = "event"
+	paramsPrefix = "params"
+)
+
+// Errors
+var (
+	ErrMissingLevel = errors.New("log line has no level")
+)
+
+// Entry is a log line read back from the text formatter output
+type Entry struct {
+	// time of the entry, zero if the timestamp was disabled
+	Time time.Time
+
+	// level the entry was logged at
+	Level rogger.Level
+
+	// log message
+	Message string
+
+	// name of the event, for entries logged as events
+	Event string
+
+	// field formatting error reported by the logger
+	Error string
+
+	// calling method and location, if the caller was reported
+	Func string
+	File string
+	Line int
+
+	// params logged with the entry, param keys which clashed with the
+	// fixed keys are restored to their original name
+	Params map[string]string
+}
+
+// Parser parses lines written by a text formatter
+type Parser struct {
+	// TimestampFormat used by the formatter, defaults to RFC3339
+	TimestampFormat string
+}
+
+// Line parses a single line using the default parser
+func Line(line string) (*Entry, error) {
+	return new(Parser).Line(line)
+}
+
+// Line parses a single line of text formatter output
+func (p *Parser) Line(line string) (*Entry, error) {
+	pairs, err := split(strings.TrimRight(line, "\r\n"))
+	if err != nil {
+		return nil, err
+	}
+	tsFormat := p.TimestampFormat
+	if tsFormat == "" {
+		tsFormat = time.RFC3339
+	}
+	entry := &Entry{Params: make(map[string]string)}
+	hasLevel := false
+	for _, pair := range pairs {
+		key, value := pair[0], pair[1]
+		switch key {
+		case timeKey:
+			entry.Time, err = time.Parse(tsFormat, value)
+			if err != nil {
+				return nil, err
+			}
+		case msgKey:
+			entry.Message = value
+		case levelKey:
+			entry.Level, err = rogger.ParseLevel(value)
+			if err != nil {
+				return nil, err
+			}
+			hasLevel = true
+		case eventKey:
+			entry.Event = value
+		case errKey:
+			entry.Error = value
+		case funcKey:
+			entry.Func = value
+		case fileKey:
+			entry.File = value
+			if i := strings.LastIndex(value, ":"); i >= 0 {
+				if line, err := strconv.Atoi(value[i+1:]); err == nil {
+					entry.File, entry.Line = value[:i], line
+				}
+			}
+		default:
+			switch key {
+			case paramsPrefix + timeKey, paramsPrefix + msgKey, paramsPrefix + levelKey,
+				paramsPrefix + errKey, paramsPrefix + funcKey, paramsPrefix + fileKey,
+				paramsPrefix + eventKey:
+				key = strings.TrimPrefix(key, paramsPrefix)
+			}
+			entry.Params[key] = value
+		}
+	}
+	if !hasLevel {
+		return nil, ErrMissingLevel
+	}
+	return entry, nil
+}
+
+// split breaks a line into its key and value pairs, unquoting the values
+func split(line string) ([][2]string, error) {
+	var pairs [][2]string
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		eq := strings.IndexByte(line[i:], '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("missing value for key %q", line[i:])
+		}
+		key := line[i : i+eq]
+		if strings.IndexByte(key, ' ') >= 0 {
+			return nil, fmt.Errorf("malformed key %q", key)
+		}
+		i += eq + 1
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for ; end < len(line); end++ {
+				if line[end] == '\\' {
+					end++
+				} else if line[end] == '"' {
+					break
+				}
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated value for key %q", key)
+			}
+			unquoted, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for key %q: %v", key, err)
+			}
+			value = unquoted
+			i = end + 1
+		} else {
+			end := strings.IndexByte(line[i:], ' ')
+			if end < 0 {
+				end = len(line) - i
+			}
+			value = line[i : i+end]
+			i += end
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs, nil
+}
+
+// Scanner reads entries from text formatter output line by line
+type Scanner struct {
+	parser  *Parser
+	scanner *bufio.Scanner
+	entry   *Entry
+	err     error
+}
+
+// NewScanner creates a scanner reading from r using the parser, the default
+// parser is used when it is nil
+func NewScanner(r io.Reader, parser *Parser) *Scanner {
+	if parser == nil {
+		parser = new(Parser)
+	}
+	return &Scanner{
+		parser:  parser,
+		scanner: bufio.NewScanner(r),
+	}
+}
+
+// Scan advances to the next entry, skipping empty lines. It returns false at
+// the end of the input or when a line can not be parsed.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.entry, s.err = s.parser.Line(line)
+		return s.err == nil
+	}
+	s.err = s.scanner.Err()
+	return false
+}
+
+// Entry returns the entry read by the last call to Scan
+func (s *Scanner) Entry() *Entry {
+	return s.entry
+}
+
+// Err returns the first error hit while scanning
+func (s *Scanner) Err() error {
+	return s.err
+}
@@ -0,0 +1,80 @@
+package rogger
+
+import (
+	"sort"
+	"strings"
+)
+
+// paramSourcesKey is the param the sources are written to
+const paramSourcesKey = "param_sources"
+
+// Param sources, as written to the param_sources param
+const (
+	// SourceCall is for the params of the log call and its entry
+	SourceCall = "call"
+
+	// SourceStatic is for the static params of the logger
+	SourceStatic = "static"
+
+	// SourceLogger is for the params set or replaced by the logger itself,
+	// such as the schema version, the sample rate and attachments
+	SourceLogger = "logger"
+)
+
+// SetParamSources sets whether the entries have the sources of their params
+func (logger *Logger) SetParamSources(enabled bool) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.ParamSources = enabled
+}
+
+// paramSources returns the sources of the params of the log call and the
+// static ones, before they are merged. Every key has its sources separated
+// by commas, from the one whose value is kept.
+func (logger *Logger) paramSources(call Params) map[string]string {
+	sources := make(map[string]string, len(call)+len(logger.StaticParams))
+	for _, params := range []struct {
+		source string
+		params Params
+	}{
+		{SourceCall, call},
+		{SourceStatic, logger.StaticParams},
+	} {
+		for k := range params.params {
+			if from, ok := sources[k]; ok {
+				sources[k] = from + "," + params.source
+			} else {
+				sources[k] = params.source
+			}
+		}
+	}
+	return sources
+}
+
+// overrideSource records the logger setting the param, nothing is recorded
+// when the sources are not tracked
+func overrideSource(sources map[string]string, key string) {
+	if sources == nil {
+		return
+	}
+	if from, ok := sources[key]; ok {
+		sources[key] = SourceLogger + "," + from
+		return
+	}
+	sources[key] = SourceLogger
+}
+
+// formatSources writes the sources as key=sources pairs sorted by key, such
+// as "env=call,static service=static"
+func formatSources(sources map[string]string) string {
+	keys := make([]string, 0, len(sources))
+	for k := range sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + sources[k]
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,74 @@
+package rogger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ReopenableFile writes to a file which can be reopened at the same path,
+// so that an external tool like logrotate can rename it and have the new
+// entries written to a fresh file, without a restart or lost writes. It is
+// safe for concurrent use.
+type ReopenableFile struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenReopenableFile opens the file for appending, creating it when missing
+func OpenReopenableFile(path string) (*ReopenableFile, error) {
+	f := &ReopenableFile{path: path}
+	file, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	f.file = file
+	return f, nil
+}
+
+func (f *ReopenableFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	return f.file.Write(p)
+}
+
+// Reopen opens the path again and closes the previous file. The previous
+// file is kept when the path can not be opened, so that writes go on.
+func (f *ReopenableFile) Reopen() error {
+	file, err := f.open()
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	previous := f.file
+	f.file = file
+	f.mu.Unlock()
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// Close closes the file, writing to it afterwards fails
+func (f *ReopenableFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *ReopenableFile) open() (*os.File, error) {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the log file, %v", err)
+	}
+	return file, nil
+}
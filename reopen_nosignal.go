@@ -0,0 +1,11 @@
+//go:build js || plan9 || roggerlite
+// +build js plan9 roggerlite
+
+package rogger
+
+// ReopenOnSignal does nothing on platforms without SIGHUP, and in the lite
+// build which leaves out signal handling, where Reopen has to be called
+// explicitly
+func (f *ReopenableFile) ReopenOnSignal() (stop func()) {
+	return func() {}
+}
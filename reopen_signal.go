@@ -0,0 +1,36 @@
+//go:build !js && !plan9 && !roggerlite
+// +build !js,!plan9,!roggerlite
+
+package rogger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReopenOnSignal reopens the file whenever the process receives SIGHUP, the
+// signal logrotate sends after renaming the file, until stop is called.
+// Failures to reopen are printed to the standard error.
+func (f *ReopenableFile) ReopenOnSignal() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := f.Reopen(); err != nil {
+					_, _ = fmt.Fprintln(os.Stderr, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
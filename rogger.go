@@ -1,8 +1,12 @@
 package rogger
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,11 +34,28 @@ func (l Level) String() string {
 	return "unknown"
 }
 
+// ParseLevel takes a level name and returns the matching level
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	}
+	return 0, fmt.Errorf("not a valid level: %q", name)
+}
+
 // Logger is the type used for main logging
 type Logger struct {
 	// it is locked with mutex before any log is sent to this
 	// default is os.Stderr.
-	// better to set it to a file, which will be rotated automatically.
+	// better to set it to a file, see RotatingFileWriter for rotating it.
 	Out io.Writer
 
 	// formatter formats logs before finally sending to the writer
@@ -46,11 +67,121 @@ type Logger struct {
 	// The logging level the logger should log at. defaults to info.
 	Level Level
 
+	// WriteTimeout bounds the time a log call waits for the output, zero to
+	// wait as long as it takes. Once a write times out, it and the entries
+	// logged after it are written in the background until it completes.
+	WriteTimeout time.Duration
+
+	// AsyncQueueSize makes the logger asynchronous when positive. The
+	// entries are formatted by the log calls and queued to be written in
+	// the background, so that slow outputs do not hold the callers back.
+	// Entries logged while the queue is full are dropped. The queue is
+	// created with the size on the first write, use Flush to wait for it and
+	// Close before exiting. The write timeout does not apply.
+	AsyncQueueSize int
+
+	// CrashDir is the directory crash files are written to on fatal logs
+	// and panics recovered with Recover, empty to disable them
+	CrashDir string
+
+	// HistorySize is the number of recent entries kept in memory, which are
+	// included in crash files
+	HistorySize int
+
+	// SchemaVersion is stamped as the schema_version param of every entry
+	// when set, so that readers can tell apart the field conventions in use
+	SchemaVersion string
+
+	// Schema the params of every entry are checked against, nil to disable
+	Schema Schema
+
+	// ExitFunc is called by Fatal to exit the application, os.Exit is used
+	// when it is nil. It can be replaced to test fatal logs.
+	ExitFunc func(int)
+
+	// ErrorHandler is called with the logger's own failures, such as
+	// formatting or write errors, as *Error values with a code classifying
+	// them. By default they are printed to os.Stderr. It may be called while
+	// the logger is locked, so it must not log through the same logger.
+	ErrorHandler func(error)
+
+	// OnDrop is called with the entries the background writes drop after a
+	// write timeout, the asynchronous writes drop, or the sampler drops, and
+	// why, so that they can be alerted on or saved elsewhere. It may be
+	// called while the logger is locked, so it must not log through the same
+	// logger.
+	OnDrop func(entry *Entry, reason DropReason)
+
+	// OnQueueHighWater is called with the depth of the background or
+	// asynchronous write queue when it reaches QueueHighWater, three
+	// quarters of its capacity by default, once until it is drained below it
+	// again. The same restrictions as OnDrop apply.
+	OnQueueHighWater func(depth int)
+	QueueHighWater   int
+
+	// Attachments store the string and byte param values longer than
+	// AttachmentThreshold bytes, which are replaced in the entries by an
+	// Attachment referencing them, so that big payloads are kept for
+	// debugging without bloating the log. Nil or a zero threshold disables
+	// it.
+	Attachments         AttachmentSink
+	AttachmentThreshold int
+
+	// Sampler drops frequent entries to keep within a volume budget, nil to
+	// keep them all
+	Sampler *AdaptiveSampler
+
+	// ParamSources adds the param_sources param to every entry, with the
+	// sources of each of its params from the one kept, such as
+	// "env=call,static" for a param of the log call overriding a static one,
+	// to debug where an unexpected value comes from. It is meant for
+	// debugging, as it costs an allocation per entry.
+	ParamSources bool
+
+	// StaticParams are added to every entry, such as the service name and
+	// environment. The params of the entry take precedence over them.
+	StaticParams Params
+
+	// TimestampInUTC converts the time of every entry to UTC before it is
+	// formatted, so that the logs of hosts in different time zones line up
+	TimestampInUTC bool
+
+	// Catalog translates the level names and the diagnostic strings of the
+	// logger, nil to keep them in english
+	Catalog Catalog
+
 	// Used to sync writing to the log. Locking is enabled by Default
 	mu mutexWrap
 
 	// Reusable empty log entries
 	entryPool sync.Pool
+
+	// recent entries
+	history entryRing
+
+	// metrics extracted from entries
+	metrics metricRules
+
+	// entries logged by level and failures
+	counters entryCounters
+
+	// rules raising the level of repeated entries
+	escalations escalationRules
+
+	// writes continuing in the background after a write timeout
+	retries retryQueue
+
+	// writes in the background when asynchronous
+	async asyncQueue
+
+	// outputs the entries are routed to by level
+	levelOutputs []levelOutput
+
+	// callbacks for level changes
+	levelChangeHandlers []func(old, new Level)
+
+	// time taken to format and write entries
+	writeLatency latencyHistogram
 }
 
 type mutexWrap struct {
@@ -98,6 +229,7 @@ func New() *Logger {
 func (logger *Logger) newEntry() *Entry {
 	entry, ok := logger.entryPool.Get().(*Entry)
 	if ok {
+		entry.debugAcquire()
 		return entry
 	}
 	return NewEntry(logger)
@@ -105,6 +237,7 @@ func (logger *Logger) newEntry() *Entry {
 
 func (logger *Logger) releaseEntry(entry *Entry) {
 	entry.Data = map[string]interface{}{}
+	entry.debugRelease()
 	logger.entryPool.Put(entry)
 }
 
@@ -124,6 +257,15 @@ func (logger *Logger) WithParams(params Params) *Entry {
 	return entry.WithParams(params)
 }
 
+// WithGroup adds the params to a group, with their keys prefixed by its
+// name and a dot. The params added to the returned entry are in the group
+// too.
+func (logger *Logger) WithGroup(name string, params Params) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithGroup(name, params)
+}
+
 // Add an error as single field to the Entry, and logs when Debug, Print, Info,
 // Warn, Error or Fatal is called.
 func (logger *Logger) WithError(err error) *Entry {
@@ -139,6 +281,64 @@ func (logger *Logger) WithTime(t time.Time) *Entry {
 	return entry.WithTime(t)
 }
 
+// Add a context to the log entry, and logs when Debug, Print, Info,
+// Warn, Error or Fatal is called.
+func (logger *Logger) WithContext(ctx context.Context) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithContext(ctx)
+}
+
+// minLevelKey is the context key of the scoped level of a logger
+type minLevelKey struct {
+	logger *Logger
+}
+
+// WithMinLevel returns a context which lowers the logging level for entries
+// logged with it, for example to enable debug logs for a single request
+// without changing the level globally.
+//    ctx = log.WithMinLevel(ctx, rogger.DebugLevel)
+//    log.WithContext(ctx).Debug("only logged for this request")
+// The logger level still applies if it is lower.
+func (logger *Logger) WithMinLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, minLevelKey{logger}, level)
+}
+
+// Overrides the output of the log entry.
+func (logger *Logger) WithOutput(w io.Writer) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithOutput(w)
+}
+
+// Replay writes an entry created elsewhere, for example by UnmarshalEntry,
+// through this logger keeping its time, level, message, params and caller.
+// Unlike Fatal, replaying a fatal entry does not exit.
+func (logger *Logger) Replay(entry *Entry) {
+	if !logger.IsLevelEnabled(entry.Level) {
+		return
+	}
+	replayed := *entry
+	replayed.Logger = logger
+	replayed.callerPending = false
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	defer bufferPool.Put(buffer)
+	replayed.Buffer = buffer
+	replayed.write()
+}
+
+// Event logs a structured event at info level, with the name as a dedicated
+// field and no message. Events are meant for analytics and routing, where a
+// stable name is easier to match than free text.
+func (logger *Logger) Event(name string, params Params) {
+	if logger.IsLevelEnabled(InfoLevel) {
+		entry := logger.newEntry()
+		defer logger.releaseEntry(entry)
+		entry.Event(name, params)
+	}
+}
+
 func (logger *Logger) Log(level Level, args ...interface{}) {
 	if logger.IsLevelEnabled(level) {
 		entry := logger.newEntry()
@@ -159,13 +359,18 @@ func (logger *Logger) Warn(args ...interface{}) {
 	logger.Log(WarnLevel, args...)
 }
 
+// Warning is an alias of Warn
+func (logger *Logger) Warning(args ...interface{}) {
+	logger.Warn(args...)
+}
+
 func (logger *Logger) Error(args ...interface{}) {
 	logger.Log(ErrorLevel, args...)
 }
 
 func (logger *Logger) Fatal(args ...interface{}) {
 	logger.Log(FatalLevel, args...)
-	os.Exit(1)
+	logger.Exit(1)
 }
 
 func (logger *Logger) Logf(level Level, format string, args ...interface{}) {
@@ -188,13 +393,18 @@ func (logger *Logger) Warnf(format string, args ...interface{}) {
 	logger.Logf(WarnLevel, format, args...)
 }
 
+// Warningf is an alias of Warnf
+func (logger *Logger) Warningf(format string, args ...interface{}) {
+	logger.Warnf(format, args...)
+}
+
 func (logger *Logger) Errorf(format string, args ...interface{}) {
 	logger.Logf(ErrorLevel, format, args...)
 }
 
 func (logger *Logger) Fatalf(format string, args ...interface{}) {
 	logger.Logf(FatalLevel, format, args...)
-	os.Exit(1)
+	logger.Exit(1)
 }
 
 func (logger *Logger) Logln(level Level, args ...interface{}) {
@@ -217,6 +427,11 @@ func (logger *Logger) Warnln(args ...interface{}) {
 	logger.Logln(WarnLevel, args...)
 }
 
+// Warningln is an alias of Warnln
+func (logger *Logger) Warningln(args ...interface{}) {
+	logger.Warnln(args...)
+}
+
 func (logger *Logger) Errorln(args ...interface{}) {
 	logger.Logln(ErrorLevel, args...)
 }
@@ -226,9 +441,35 @@ func (logger *Logger) Fatalln(args ...interface{}) {
 	logger.Exit(1)
 }
 
+// handleError reports a failure of the logger to the error handler, as an
+// *Error with its code
+func (logger *Logger) handleError(code ErrorCode, err error) {
+	logger.reportError(logger.ErrorHandler, code, err)
+}
+
+// reportError counts the failure and passes it to the handler, or prints it
+// when there is none
+func (logger *Logger) reportError(handler func(error), code ErrorCode, err error) {
+	logger.counters.countError()
+	if handler != nil {
+		handler(&Error{Code: code, Err: err})
+		return
+	}
+	_, _ = fmt.Fprintln(os.Stderr, err)
+}
+
 // exit function called to exit the application
 // having a function makes us able to use the code commonly
-func (*Logger) Exit(code int) {
+func (logger *Logger) Exit(code int) {
+	if logger.AsyncQueueSize > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), exitFlushTimeout)
+		_ = logger.Flush(ctx)
+		cancel()
+	}
+	if logger.ExitFunc != nil {
+		logger.ExitFunc(code)
+		return
+	}
 	os.Exit(code)
 }
 
@@ -237,10 +478,28 @@ func (logger *Logger) SetNoLock() {
 }
 
 // SetLevel sets the logger level.
+// The level change callbacks are called if the level changed.
 func (logger *Logger) SetLevel(level Level) {
 	logger.mu.lock()
-	defer logger.mu.unlock()
+	old := logger.Level
 	logger.Level = level
+	handlers := logger.levelChangeHandlers
+	logger.mu.unlock()
+	if old != level {
+		for _, handler := range handlers {
+			handler(old, level)
+		}
+	}
+}
+
+// OnLevelChange registers a callback called whenever SetLevel changes the
+// level, so that behaviour depending on the level can be updated.
+func (logger *Logger) OnLevelChange(handler func(old, new Level)) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	handlers := make([]func(old, new Level), len(logger.levelChangeHandlers), len(logger.levelChangeHandlers)+1)
+	copy(handlers, logger.levelChangeHandlers)
+	logger.levelChangeHandlers = append(handlers, handler)
 }
 
 // SetFormatter sets the logger formatter
@@ -259,8 +518,104 @@ func (logger *Logger) SetOutput(output io.Writer) {
 	logger.Out = output
 }
 
+// SetWriteTimeout sets the time a log call waits for the output
+func (logger *Logger) SetWriteTimeout(timeout time.Duration) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.WriteTimeout = timeout
+}
+
+// SetCrashDir sets the directory crash files are written to
+func (logger *Logger) SetCrashDir(dir string) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.CrashDir = dir
+}
+
+// SetHistorySize sets the number of recent entries kept in memory
+func (logger *Logger) SetHistorySize(size int) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.HistorySize = size
+}
+
+// SetSchemaVersion sets the version stamped on every entry
+func (logger *Logger) SetSchemaVersion(version string) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.SchemaVersion = version
+}
+
+// SetSchema sets the schema params are validated against
+func (logger *Logger) SetSchema(schema Schema) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.Schema = schema
+}
+
+// SetErrorHandler sets the handler of the logger's own failures
+func (logger *Logger) SetErrorHandler(handler func(error)) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.ErrorHandler = handler
+}
+
+// SetOnDrop sets the callback for the entries dropped by background writes
+func (logger *Logger) SetOnDrop(onDrop func(entry *Entry, reason DropReason)) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.OnDrop = onDrop
+}
+
+// SetOnQueueHighWater sets the callback for the background write queue
+// reaching the mark, zero for the default one
+func (logger *Logger) SetOnQueueHighWater(mark int, onHighWater func(depth int)) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.QueueHighWater = mark
+	logger.OnQueueHighWater = onHighWater
+}
+
+// SetStaticParams sets the params added to every entry
+func (logger *Logger) SetStaticParams(params Params) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.StaticParams = params
+}
+
+// SetTimestampInUTC sets whether the time of entries is converted to UTC
+func (logger *Logger) SetTimestampInUTC(utc bool) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.TimestampInUTC = utc
+}
+
+// SetCatalog sets the catalog translating the strings of the logger
+func (logger *Logger) SetCatalog(catalog Catalog) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.Catalog = catalog
+}
+
 func (logger *Logger) SetReportCaller(reportCaller bool) {
 	logger.mu.lock()
 	defer logger.mu.unlock()
 	logger.ReportCaller = reportCaller
 }
+
+// LevelWriter is an optional interface for outputs which need to know the
+// level of the entry being written, for example to route it to a different
+// destination. When the logger output implements it, WriteLevel is called
+// instead of Write.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (n int, err error)
+}
+
+// writeLevel writes to the output, passing on the level when it is supported
+func writeLevel(w io.Writer, level Level, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
@@ -0,0 +1,232 @@
+package rogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotating file constants
+const (
+	defaultMaxFileSize = 100 << 20
+	backupTimeFormat   = "2006-01-02T15-04-05.000"
+	compressSuffix     = ".gz"
+	logFilePerm        = 0644
+	logDirPerm         = 0755
+)
+
+// RotatingFileWriter writes to a file, rotating it once it grows past its
+// maximum size. The rotated file is renamed with the time of the rotation,
+// such as app-2006-01-02T15-04-05.000.log for app.log, and the old backups
+// are compressed and removed in the background. It is safe for concurrent
+// use.
+type RotatingFileWriter struct {
+	// Filename of the file written, along with its backups in the same
+	// directory
+	Filename string
+
+	// MaxSize of the file in bytes before it is rotated, 100 MiB by default
+	MaxSize int64
+
+	// MaxBackups is the number of backups kept, and MaxAge the time they are
+	// kept for, zero keeps all of them
+	MaxBackups int
+	MaxAge     time.Duration
+
+	// Compress gzips the backups
+	Compress bool
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// the backups are cleaned up one rotation at a time
+	cleanupMu sync.Mutex
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize() {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file right away, whatever its size
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// Close closes the file, it is opened again on the next write
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotatingFileWriter) maxSize() int64 {
+	if w.MaxSize <= 0 {
+		return defaultMaxFileSize
+	}
+	return w.MaxSize
+}
+
+// open opens the file for appending, creating it when missing
+func (w *RotatingFileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.Filename), logDirPerm); err != nil {
+		return fmt.Errorf("failed to create the log directory, %v", err)
+	}
+	file, err := os.OpenFile(w.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open the log file, %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat the log file, %v", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate renames the file to a backup and opens a new one, it must be
+// called with the lock held
+func (w *RotatingFileWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close the log file, %v", err)
+		}
+		w.file = nil
+	}
+	if _, err := os.Stat(w.Filename); err == nil {
+		if err = os.Rename(w.Filename, w.backupName(time.Now())); err != nil {
+			return fmt.Errorf("failed to rename the log file, %v", err)
+		}
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	go w.cleanup()
+	return nil
+}
+
+// backupName returns the name of the backup of the file rotated at the time
+func (w *RotatingFileWriter) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	return filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+// nameParts splits the file name around the place of the backup time
+func (w *RotatingFileWriter) nameParts() (dir, prefix, ext string) {
+	dir, base := filepath.Split(w.Filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backup is a rotated file with the time it was rotated at
+type backup struct {
+	path string
+	time time.Time
+}
+
+// cleanup compresses the backups and removes the ones beyond the limits
+func (w *RotatingFileWriter) cleanup() {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-w.MaxAge)
+	for i, b := range backups {
+		if (w.MaxBackups > 0 && i >= w.MaxBackups) || (w.MaxAge > 0 && b.time.Before(cutoff)) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if w.Compress && !strings.HasSuffix(b.path, compressSuffix) {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// backups lists the backups of the file, the most recent first
+func (w *RotatingFileWriter) backups() ([]backup, error) {
+	dir, prefix, ext := w.nameParts()
+	if dir == "" {
+		dir = "."
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix)
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+// compressFile gzips the file, removing the original once done
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFilePerm)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path + compressSuffix)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,154 @@
+package rogger
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sampler defaults
+const (
+	defaultSampleWindow  = time.Second
+	defaultSampleRateKey = "sample_rate"
+
+	// maxSamplerKeys bounds the messages tracked by a sampler, the ones
+	// beyond it are sampled together
+	maxSamplerKeys = 1024
+)
+
+// AdaptiveSampler keeps the entries logged within a budget of entries per
+// second by sampling the frequent messages, while the rare ones are all kept.
+// Every window, the budget is shared between the messages logged in the last
+// one: those logged less than their share are kept, and the others are kept
+// one in N, with N chosen to fit the rest of the budget. The kept entries of
+// sampled messages have their N as the sample_rate param, so that counts can
+// be re-weighted downstream.
+//
+// Entries are told apart by message, or event name for events, so messages
+// should not interpolate values which are better logged as params. The
+// message is built before the entry is sampled, so the sampled entries still
+// pay for formatting it. It is safe for concurrent use, and can be shared by
+// loggers to share the budget.
+//
+//	logger.SetSampler(&AdaptiveSampler{
+//		EntriesPerSecond: 1000,
+//		MaxLevel:         InfoLevel,
+//	})
+type AdaptiveSampler struct {
+	// EntriesPerSecond is the budget of entries kept, zero to keep them all
+	EntriesPerSecond float64
+
+	// MaxLevel is the highest level sampled, entries above it are always
+	// kept. It defaults to debug, set it to info to sample info entries too.
+	MaxLevel Level
+
+	// Window the rates are adjusted over, a second by default
+	Window time.Duration
+
+	// RateKey is the param the sample rate is written to, sample_rate by
+	// default
+	RateKey string
+
+	mu      sync.Mutex
+	start   time.Time
+	counts  map[string]int
+	rates   map[string]int
+	sampled uint64
+}
+
+// SetSampler sets the sampler of the entries, nil to keep them all
+func (logger *Logger) SetSampler(sampler *AdaptiveSampler) {
+	logger.mu.lock()
+	defer logger.mu.unlock()
+	logger.Sampler = sampler
+}
+
+// sample counts the entry and checks whether it is kept, returning the rate
+// it was sampled at
+func (s *AdaptiveSampler) sample(entry *Entry) (rate int, keep bool) {
+	if entry.Level > s.MaxLevel || s.EntriesPerSecond <= 0 {
+		return 1, true
+	}
+	key := firstNonEmpty(entry.Message, entry.EventName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(entry.Time)
+	if _, ok := s.counts[key]; !ok && len(s.counts) >= maxSamplerKeys {
+		key = ""
+	}
+	s.counts[key]++
+	rate = s.rates[key]
+	if rate <= 1 {
+		return 1, true
+	}
+	if (s.counts[key]-1)%rate != 0 {
+		s.sampled++
+		return rate, false
+	}
+	return rate, true
+}
+
+// advance starts a new window once the current one is over, with the rates
+// computed from its counts
+func (s *AdaptiveSampler) advance(now time.Time) {
+	window := s.Window
+	if window <= 0 {
+		window = defaultSampleWindow
+	}
+	if s.counts != nil && now.Sub(s.start) < window {
+		return
+	}
+	if s.counts != nil && now.Sub(s.start) < 2*window {
+		s.rates = sampleRates(s.counts, s.EntriesPerSecond*window.Seconds())
+	} else {
+		// nothing was logged in the last window
+		s.rates = nil
+	}
+	s.start = now
+	s.counts = make(map[string]int, len(s.counts))
+}
+
+// sampleRates shares the budget between the keys, from the rarest to the most
+// frequent, so that the rare ones are kept and the frequent ones sampled. A
+// key is always kept at least once per window.
+func sampleRates(counts map[string]int, budget float64) map[string]int {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return counts[keys[i]] < counts[keys[j]]
+	})
+	rates := make(map[string]int, len(keys))
+	for i, key := range keys {
+		count := float64(counts[key])
+		share := budget / float64(len(keys)-i)
+		if count <= share {
+			budget -= count
+			continue
+		}
+		rate := counts[key]
+		if share >= 1 {
+			rate = int(math.Ceil(count / share))
+		}
+		rates[key] = rate
+		budget -= count / float64(rate)
+	}
+	return rates
+}
+
+// rateKey returns the param the sample rate is written to
+func (s *AdaptiveSampler) rateKey() string {
+	if s.RateKey == "" {
+		return defaultSampleRateKey
+	}
+	return s.RateKey
+}
+
+// sampledEntries returns the number of entries the sampler dropped
+func (s *AdaptiveSampler) sampledEntries() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sampled
+}
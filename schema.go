@@ -0,0 +1,145 @@
+package rogger
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Kind is the expected kind of a param value in a schema
+type Kind uint32
+
+// Kinds
+const (
+	// AnyKind accepts any value
+	AnyKind Kind = iota
+	// StringKind accepts strings
+	StringKind
+	// IntKind accepts signed and unsigned integers
+	IntKind
+	// FloatKind accepts floating point numbers and integers
+	FloatKind
+	// BoolKind accepts booleans
+	BoolKind
+	// TimeKind accepts time.Time values
+	TimeKind
+	// DurationKind accepts time.Duration values
+	DurationKind
+	// ErrorKind accepts errors
+	ErrorKind
+)
+
+// convert kind to a string
+func (k Kind) String() string {
+	switch k {
+	case AnyKind:
+		return "any"
+	case StringKind:
+		return "string"
+	case IntKind:
+		return "int"
+	case FloatKind:
+		return "float"
+	case BoolKind:
+		return "bool"
+	case TimeKind:
+		return "time"
+	case DurationKind:
+		return "duration"
+	case ErrorKind:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParamRule describes a single param in a schema
+type ParamRule struct {
+	// Kind the value is expected to have
+	Kind Kind
+
+	// Required params are reported when missing from an entry
+	Required bool
+}
+
+// Schema maps param keys to their rules. When set on a logger, every logged
+// entry is checked against it and mismatches, including params which are
+// not part of the schema, are reported to the logger error handler. The
+// params of the entries are checked merged with the static params of the
+// logger, so these have to be declared too, while the ones the logger adds
+// such as the schema version, sample rate and attachments are not checked.
+// It is meant for development as checking adds to the cost of every log.
+type Schema map[string]ParamRule
+
+// SchemaError is reported for every param not matching the schema
+type SchemaError struct {
+	Key    string
+	Reason string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("param %q %s", e.Key, e.Reason)
+}
+
+// validate checks the params against the schema
+func (s Schema) validate(data Params) []error {
+	var errs []error
+	for k, v := range data {
+		rule, ok := s[k]
+		if !ok {
+			errs = append(errs, &SchemaError{Key: k, Reason: "is not in the schema"})
+			continue
+		}
+		if !rule.Kind.matches(v) {
+			errs = append(errs, &SchemaError{Key: k, Reason: fmt.Sprintf("is %T, expected %s", v, rule.Kind)})
+		}
+	}
+	for k, rule := range s {
+		if _, ok := data[k]; rule.Required && !ok {
+			errs = append(errs, &SchemaError{Key: k, Reason: "is required"})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].(*SchemaError).Key < errs[j].(*SchemaError).Key
+	})
+	return errs
+}
+
+// matches checks whether the value is of the kind
+func (k Kind) matches(v interface{}) bool {
+	switch k {
+	case StringKind:
+		_, ok := v.(string)
+		return ok
+	case IntKind:
+		switch v := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case decodedNumber:
+			_, err := v.Int64()
+			return err == nil
+		}
+		return false
+	case FloatKind:
+		switch v := v.(type) {
+		case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case decodedNumber:
+			_, err := v.Float64()
+			return err == nil
+		}
+		return false
+	case BoolKind:
+		_, ok := v.(bool)
+		return ok
+	case TimeKind:
+		_, ok := v.(time.Time)
+		return ok
+	case DurationKind:
+		_, ok := v.(time.Duration)
+		return ok
+	case ErrorKind:
+		_, ok := v.(error)
+		return ok
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package rogger
+
+import "fmt"
+
+// schemaVersionKey is the param the schema version is stamped as
+const schemaVersionKey = "schema_version"
+
+// Migration converts params from one schema version to another
+type Migration func(Params) Params
+
+// SchemaMigrations holds the migrations between schema versions, so that
+// tools reading logs written with older field conventions can convert them
+// to the version they understand.
+//
+//	migrations := new(rogger.SchemaMigrations)
+//	migrations.Register("1", "2", func(p rogger.Params) rogger.Params {
+//		p["user_id"] = p["uid"]
+//		delete(p, "uid")
+//		return p
+//	})
+//	params, err := migrations.Migrate(params, "2")
+type SchemaMigrations struct {
+	steps map[string]migrationStep
+}
+
+type migrationStep struct {
+	to        string
+	migration Migration
+}
+
+// Register adds the migration from a version to the next one
+func (m *SchemaMigrations) Register(from, to string, migration Migration) {
+	if m.steps == nil {
+		m.steps = make(map[string]migrationStep)
+	}
+	m.steps[from] = migrationStep{to: to, migration: migration}
+}
+
+// Migrate converts the params to the target version, following the
+// registered migrations from the version stamped on them. The params are
+// copied before they are migrated.
+func (m *SchemaMigrations) Migrate(params Params, target string) (Params, error) {
+	version := fmt.Sprint(params[schemaVersionKey])
+	if _, ok := params[schemaVersionKey]; !ok {
+		return nil, fmt.Errorf("params have no %s", schemaVersionKey)
+	}
+	migrated := make(Params, len(params))
+	for k, v := range params {
+		migrated[k] = v
+	}
+	visited := make(map[string]bool)
+	for version != target {
+		step, ok := m.steps[version]
+		if !ok || visited[version] {
+			return nil, fmt.Errorf("no migration from schema version %s to %s", version, target)
+		}
+		visited[version] = true
+		migrated = step.migration(migrated)
+		version = step.to
+		migrated[schemaVersionKey] = version
+	}
+	return migrated, nil
+}
+
+// withParam returns a copy of the params with the param added
+func withParam(data Params, key string, value interface{}) Params {
+	params := make(Params, len(data)+1)
+	for k, v := range data {
+		params[k] = v
+	}
+	params[key] = value
+	return params
+}
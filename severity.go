@@ -0,0 +1,97 @@
+package rogger
+
+// Severity is the representation of a level in another logging system
+type Severity struct {
+	Number int
+	Name   string
+}
+
+// SeverityProfile maps levels to the severities of a logging system, so that
+// a level translates the same way in every formatter and output using it.
+// Custom profiles can be created to match other conventions.
+type SeverityProfile map[Level]Severity
+
+// Built in severity profiles
+var (
+	// SyslogSeverities are the syslog severity numbers and keywords
+	SyslogSeverities = SeverityProfile{
+		DebugLevel: {Number: 7, Name: "debug"},
+		InfoLevel:  {Number: 6, Name: "info"},
+		WarnLevel:  {Number: 4, Name: "warning"},
+		ErrorLevel: {Number: 3, Name: "err"},
+		FatalLevel: {Number: 2, Name: "crit"},
+	}
+
+	// RFC5424Severities are the syslog severities with the names used by
+	// RFC 5424
+	RFC5424Severities = SeverityProfile{
+		DebugLevel: {Number: 7, Name: "Debug"},
+		InfoLevel:  {Number: 6, Name: "Informational"},
+		WarnLevel:  {Number: 4, Name: "Warning"},
+		ErrorLevel: {Number: 3, Name: "Error"},
+		FatalLevel: {Number: 2, Name: "Critical"},
+	}
+
+	// CloudSeverities are the Google Cloud Logging severities
+	CloudSeverities = SeverityProfile{
+		DebugLevel: {Number: 100, Name: "DEBUG"},
+		InfoLevel:  {Number: 200, Name: "INFO"},
+		WarnLevel:  {Number: 400, Name: "WARNING"},
+		ErrorLevel: {Number: 500, Name: "ERROR"},
+		FatalLevel: {Number: 600, Name: "CRITICAL"},
+	}
+
+	// CEFSeverities are the ArcSight CEF severities from 0 to 10
+	CEFSeverities = SeverityProfile{
+		DebugLevel: {Number: 1, Name: "Low"},
+		InfoLevel:  {Number: 3, Name: "Low"},
+		WarnLevel:  {Number: 5, Name: "Medium"},
+		ErrorLevel: {Number: 8, Name: "High"},
+		FatalLevel: {Number: 10, Name: "Very-High"},
+	}
+
+	// OTLPSeverities are the OpenTelemetry log severity numbers and texts
+	OTLPSeverities = SeverityProfile{
+		DebugLevel: {Number: 5, Name: "DEBUG"},
+		InfoLevel:  {Number: 9, Name: "INFO"},
+		WarnLevel:  {Number: 13, Name: "WARN"},
+		ErrorLevel: {Number: 17, Name: "ERROR"},
+		FatalLevel: {Number: 21, Name: "FATAL"},
+	}
+)
+
+// Severity returns the severity of the level. Levels missing from the
+// profile get the severity of the closest lower level in it, or the lowest
+// one if there is none.
+func (p SeverityProfile) Severity(level Level) Severity {
+	if s, ok := p[level]; ok {
+		return s
+	}
+	var (
+		closest  Severity
+		found    bool
+		foundLvl Level
+	)
+	for l, s := range p {
+		if l < level && (!found || l > foundLvl) {
+			closest, found, foundLvl = s, true, l
+		}
+	}
+	if found {
+		return closest
+	}
+	for l, s := range p {
+		if !found || l < foundLvl {
+			closest, found, foundLvl = s, true, l
+		}
+	}
+	return closest
+}
+
+// orDefault returns the profile, or the default one when it is nil
+func (p SeverityProfile) orDefault(profile SeverityProfile) SeverityProfile {
+	if p == nil {
+		return profile
+	}
+	return p
+}
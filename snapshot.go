@@ -0,0 +1,34 @@
+package rogger
+
+import (
+	"errors"
+	"time"
+)
+
+// Errors
+var (
+	ErrNoHistory     = errors.New("no history kept, set HistorySize to keep recent entries")
+	ErrInvalidWindow = errors.New("snapshot window ends before it starts")
+)
+
+// Snapshot returns the recent entries logged from the time up to the other,
+// both included and zero for no bound, oldest first, so that a support tool
+// or admin endpoint can pull the entries around an incident. They are taken
+// from the HistorySize entries kept in memory, older ones can be read from
+// the files written with the jsonl package using jsonl.ReadRange.
+func (logger *Logger) Snapshot(from, to time.Time) ([]Entry, error) {
+	if logger.HistorySize <= 0 {
+		return nil, ErrNoHistory
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, ErrInvalidWindow
+	}
+	var entries []Entry
+	for _, entry := range logger.history.recent() {
+		if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && entry.Time.After(to)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
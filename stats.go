@@ -0,0 +1,170 @@
+package rogger
+
+import (
+	"sync"
+	"time"
+)
+
+// upper bounds of the write latency histogram buckets, the last bucket has
+// no upper bound
+var latencyBounds = []time.Duration{
+	10 * time.Microsecond,
+	25 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// Stats is a snapshot of the logger internals
+type Stats struct {
+	// number of entries formatted and written
+	Writes uint64 `json:"writes"`
+
+	// number of entries logged at each level, by level name
+	Levels map[string]uint64 `json:"levels"`
+
+	// number of failures of the logger, such as write errors, reported to
+	// the error handler
+	Errors uint64 `json:"errors"`
+
+	// number of entries dropped by background and asynchronous writes
+	Dropped uint64 `json:"dropped"`
+
+	// number of entries dropped by the sampler
+	Sampled uint64 `json:"sampled"`
+
+	// number of entries waiting to be written in the background, or
+	// asynchronously
+	QueueDepth int `json:"queue_depth"`
+
+	// time taken to format and write entries
+	WriteLatency LatencyStats `json:"write_latency"`
+
+	// metrics extracted from entries by the metric rules, by name
+	Metrics map[string]MetricStats `json:"metrics,omitempty"`
+}
+
+// LatencyStats summarizes the latency histogram. Percentiles are reported
+// as the upper bound of the bucket they fall in, or Max when it is lower.
+type LatencyStats struct {
+	Mean time.Duration `json:"mean_ns"`
+	Max  time.Duration `json:"max_ns"`
+	P50  time.Duration `json:"p50_ns"`
+	P90  time.Duration `json:"p90_ns"`
+	P99  time.Duration `json:"p99_ns"`
+}
+
+// latencyHistogram records durations into fixed buckets
+type latencyHistogram struct {
+	mu     sync.Mutex
+	counts [17]uint64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+	h.mu.Lock()
+	h.counts[i]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+	h.mu.Unlock()
+}
+
+func (h *latencyHistogram) snapshot() (uint64, LatencyStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return 0, LatencyStats{}
+	}
+	return h.count, LatencyStats{
+		Mean: h.sum / time.Duration(h.count),
+		Max:  h.max,
+		P50:  h.percentile(0.5),
+		P90:  h.percentile(0.9),
+		P99:  h.percentile(0.99),
+	}
+}
+
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	rank := uint64(p*float64(h.count) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank && i < len(latencyBounds) && latencyBounds[i] < h.max {
+			return latencyBounds[i]
+		}
+		if seen >= rank {
+			break
+		}
+	}
+	return h.max
+}
+
+// Stats returns a snapshot of the logger internals, such as the latency of
+// formatting and writing entries
+func (logger *Logger) Stats() Stats {
+	var s Stats
+	s.Writes, s.WriteLatency = logger.writeLatency.snapshot()
+	s.Levels, s.Errors = logger.counters.snapshot()
+	s.Dropped = logger.retries.droppedWrites() + logger.async.droppedWrites()
+	s.QueueDepth = logger.retries.depth() + logger.async.depth()
+	if logger.Sampler != nil {
+		s.Sampled = logger.Sampler.sampledEntries()
+	}
+	s.Metrics = logger.metrics.snapshot()
+	return s
+}
+
+// entryCounters counts the entries logged by level and the failures
+type entryCounters struct {
+	mu     sync.Mutex
+	levels [FatalLevel + 1]uint64
+	errors uint64
+}
+
+func (c *entryCounters) countLevel(level Level) {
+	if level > FatalLevel {
+		return
+	}
+	c.mu.Lock()
+	c.levels[level]++
+	c.mu.Unlock()
+}
+
+func (c *entryCounters) countError() {
+	c.mu.Lock()
+	c.errors++
+	c.mu.Unlock()
+}
+
+func (c *entryCounters) snapshot() (map[string]uint64, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	levels := make(map[string]uint64, len(c.levels))
+	for level, count := range c.levels {
+		levels[Level(level).String()] = count
+	}
+	return levels, c.errors
+}
@@ -0,0 +1,35 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// StatsHandler serves the stats of the logger as json, along with its
+// level. It can be mounted on a debug mux next to pprof.
+//
+//	http.Handle("/debug/rogger", logger.StatsHandler())
+func (logger *Logger) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.mu.lock()
+		level := logger.Level
+		logger.mu.unlock()
+		response := struct {
+			Level string `json:"level"`
+			Stats
+		}{
+			Level: level.String(),
+			Stats: logger.Stats(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package rogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Facility is the syslog facility of the messages
+type Facility int
+
+// Facilities, the kernel facility is left out as it is reserved for the
+// kernel, so that the zero value is the user facility
+const (
+	FacilityUser Facility = iota + 1
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityLocal0 Facility = iota + 5
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// syslog constants
+const (
+	syslogVersion           = 1
+	syslogNil               = "-"
+	syslogTimestampFormat   = "2006-01-02T15:04:05.000000Z07:00"
+	defaultStructuredDataID = "params@32473"
+)
+
+// SyslogFormatter formats entries as RFC 5424 syslog messages, which can be
+// sent over any syslog transport. The params, along with the error and the
+// caller, are written as a structured data element.
+type SyslogFormatter struct {
+	// Facility of the messages, defaults to FacilityUser
+	Facility Facility
+
+	// Header fields, the host name, executable name and process id are
+	// used by default
+	Hostname string
+	AppName  string
+	ProcID   string
+	MsgID    string
+
+	// Severities the levels are mapped with, defaults to RFC5424Severities
+	Severities SeverityProfile
+
+	// StructuredDataID of the params element, defaults to params@32473
+	StructuredDataID string
+
+	// NewlineMode is how newlines in the message and params are written,
+	// escaped by default as most transports frame messages by line
+	NewlineMode NewlineMode
+
+	defaultsOnce sync.Once
+	hostname     string
+	appName      string
+	procID       string
+}
+
+func (f *SyslogFormatter) Format(entry *Entry) ([]byte, error) {
+	f.defaultsOnce.Do(func() {
+		f.hostname, _ = os.Hostname()
+		f.appName = filepath.Base(os.Args[0])
+		f.procID = strconv.Itoa(os.Getpid())
+	})
+	facility := f.Facility
+	if facility == 0 {
+		facility = FacilityUser
+	}
+	buffer := entry.Buffer
+	if buffer == nil {
+		buffer = &bytes.Buffer{}
+	}
+	_, _ = fmt.Fprintf(buffer, "<%d>%d %s %s %s %s %s ",
+		int(facility)*8+f.Severities.orDefault(RFC5424Severities).Severity(entry.Level).Number,
+		syslogVersion,
+		entry.Time.Format(syslogTimestampFormat),
+		syslogHeader(firstNonEmpty(f.Hostname, f.hostname), 255),
+		syslogHeader(firstNonEmpty(f.AppName, f.appName), 48),
+		syslogHeader(firstNonEmpty(f.ProcID, f.procID), 128),
+		syslogHeader(f.MsgID, 32),
+	)
+	f.appendStructuredData(buffer, entry)
+	if entry.Message != "" {
+		buffer.WriteByte(' ')
+		buffer.WriteString(f.NewlineMode.apply(entry.Message))
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// appendStructuredData writes the params element, or the nil value if there
+// is nothing to write
+func (f *SyslogFormatter) appendStructuredData(buffer *bytes.Buffer, entry *Entry) {
+	data := make(Params, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	hasCaller := entry.HasCaller()
+	fixParamsClash(data, entry)
+	if entry.EventName != "" {
+		data[eventKey] = entry.EventName
+	}
+	if entry.err != "" {
+		data[errKey] = entry.err
+	}
+	if hasCaller {
+		caller := entry.GetCaller()
+		data[funcKey] = caller.Function
+		data[fileKey] = fmt.Sprintf("%s:%d", caller.File, caller.Line)
+	}
+	if len(data) == 0 {
+		buffer.WriteString(syslogNil)
+		return
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	id := f.StructuredDataID
+	if id == "" {
+		id = defaultStructuredDataID
+	}
+	buffer.WriteByte('[')
+	buffer.WriteString(syslogName(id))
+	for _, k := range keys {
+		buffer.WriteByte(' ')
+		buffer.WriteString(syslogName(k))
+		buffer.WriteString(`="`)
+		value, ok := data[k].(string)
+		if !ok {
+			value = fmt.Sprint(data[k])
+		}
+		for _, r := range f.NewlineMode.apply(value) {
+			if r == '"' || r == '\\' || r == ']' {
+				buffer.WriteByte('\\')
+			}
+			buffer.WriteRune(r)
+		}
+		buffer.WriteByte('"')
+	}
+	buffer.WriteByte(']')
+}
+
+// syslogHeader keeps the printable characters of a header field, up to the
+// maximum length allowed
+func syslogHeader(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return syslogNil
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+
+// syslogName keeps the characters allowed in structured data names
+func syslogName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		return "_"
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
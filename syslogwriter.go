@@ -0,0 +1,173 @@
+//go:build !roggerlite
+// +build !roggerlite
+
+package rogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFraming is the syslog message format the SyslogWriter writes
+type SyslogFraming int
+
+// Syslog framings
+const (
+	// SyslogRFC5424 writes RFC 5424 messages, octet counted over tcp
+	SyslogRFC5424 SyslogFraming = iota
+
+	// SyslogRFC3164 writes the older BSD syslog messages, newline
+	// terminated over tcp
+	SyslogRFC3164
+)
+
+// local syslog sockets, in the order they are tried
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// ErrNoSyslog is returned when no local syslog socket could be reached
+var ErrNoSyslog = errors.New("no local syslog socket found")
+
+// SyslogWriter writes the entries to syslog, either the local daemon or a
+// remote one over udp or tcp. The formatted entries are the messages, with
+// the syslog header added, so the logger formatter should only write the
+// message and params, such as a TextFormatter with timestamps disabled. The
+// levels are mapped to syslog severities. The connection is made on the
+// first write, and made again when a write fails. It is safe for concurrent
+// use.
+type SyslogWriter struct {
+	// Network and Address of the syslog daemon, such as udp and
+	// logs.example.com:514, the local one is used when Network is empty
+	Network string
+	Address string
+
+	// Framing of the messages, RFC 5424 by default
+	Framing SyslogFraming
+
+	// Facility of the messages, defaults to FacilityUser
+	Facility Facility
+
+	// Hostname and AppName of the header, the host name and executable name
+	// are used by default
+	Hostname string
+	AppName  string
+
+	// Severities the levels are mapped with, defaults to SyslogSeverities
+	Severities SeverityProfile
+
+	mu       sync.Mutex
+	conn     net.Conn
+	stream   bool
+	hostname string
+	appName  string
+	procID   string
+}
+
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(InfoLevel, p)
+}
+
+func (w *SyslogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+	msg := w.message(level, bytes.TrimRight(p, "\n"))
+	if _, err := w.conn.Write(msg); err != nil {
+		// the daemon may have restarted, so it is tried once more
+		_ = w.conn.Close()
+		w.conn = nil
+		if err = w.connect(); err != nil {
+			return 0, err
+		}
+		if _, err = w.conn.Write(msg); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close closes the connection, it is made again on the next write
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// connect dials the daemon, it must be called with the lock held
+func (w *SyslogWriter) connect() error {
+	if w.hostname == "" {
+		w.hostname, _ = os.Hostname()
+		w.appName = filepath.Base(os.Args[0])
+		w.procID = strconv.Itoa(os.Getpid())
+	}
+	if w.Network != "" {
+		conn, err := net.Dial(w.Network, w.Address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog, %v", err)
+		}
+		w.conn = conn
+		w.stream = w.Network == "tcp" || w.Network == "tcp4" || w.Network == "tcp6" || w.Network == "unix"
+		return nil
+	}
+	for _, path := range syslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				w.stream = network == "unix"
+				return nil
+			}
+		}
+	}
+	return ErrNoSyslog
+}
+
+// message frames the formatted entry as a syslog message
+func (w *SyslogWriter) message(level Level, p []byte) []byte {
+	facility := w.Facility
+	if facility == 0 {
+		facility = FacilityUser
+	}
+	priority := int(facility)*8 + w.Severities.orDefault(SyslogSeverities).Severity(level).Number
+	hostname := firstNonEmpty(w.Hostname, w.hostname)
+	appName := firstNonEmpty(w.AppName, w.appName)
+	var msg bytes.Buffer
+	if w.Framing == SyslogRFC3164 {
+		if w.Network == "" {
+			// the local daemon adds the host name itself
+			_, _ = fmt.Fprintf(&msg, "<%d>%s %s[%s]: ", priority, time.Now().Format(time.Stamp), appName, w.procID)
+		} else {
+			_, _ = fmt.Fprintf(&msg, "<%d>%s %s %s[%s]: ", priority, time.Now().Format(time.Stamp), hostname, appName, w.procID)
+		}
+		msg.Write(p)
+		if w.stream {
+			msg.WriteByte('\n')
+		}
+		return msg.Bytes()
+	}
+	_, _ = fmt.Fprintf(&msg, "<%d>%d %s %s %s %s - - ", priority, syslogVersion,
+		time.Now().Format(syslogTimestampFormat),
+		syslogHeader(hostname, 255),
+		syslogHeader(appName, 48),
+		syslogHeader(w.procID, 128),
+	)
+	msg.Write(p)
+	if !w.stream {
+		return msg.Bytes()
+	}
+	return append([]byte(strconv.Itoa(msg.Len())+" "), msg.Bytes()...)
+}
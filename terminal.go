@@ -0,0 +1,45 @@
+package rogger
+
+import (
+	"io"
+	"os"
+)
+
+// ansi color codes
+const (
+	colorReset  = "\x1b[0m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorPurple = "\x1b[35m"
+	colorCyan   = "\x1b[36m"
+	colorGray   = "\x1b[37m"
+)
+
+// isTerminal checks whether the writer is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// levelColor returns the color a level is displayed with
+func levelColor(level Level) string {
+	switch level {
+	case DebugLevel:
+		return colorGray
+	case InfoLevel:
+		return colorCyan
+	case WarnLevel:
+		return colorYellow
+	case ErrorLevel:
+		return colorRed
+	}
+	return colorPurple
+}
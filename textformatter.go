@@ -3,57 +3,208 @@ package rogger
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 type TextFormatter struct {
 	// Disable timestamp logging
 	DisableTimestamp bool
 
+	// HideTimestampBelow leaves out the timestamp of entries below the
+	// level, for example to quiet debug lines while developing and keep it
+	// on warnings and errors
+	HideTimestampBelow Level
+
 	// TimestampFormat to use for display when a full timestamp is printed
 	TimestampFormat string
 
+	// EpochTimestamp writes the time as a number of units since the unix
+	// epoch instead of formatting it
+	EpochTimestamp EpochUnit
+
 	// The fields are sorted by default for a consistent output.
 	DisableSorting bool
+
+	// SortingFunc orders the param keys in place instead of sorting them
+	// alphabetically, for example to put the request id first
+	SortingFunc func([]string)
+
+	// Levels are colored when the output is a terminal. ForceColors colors
+	// them for any output, and DisableColors never does.
+	ForceColors   bool
+	DisableColors bool
+
+	// Theme styles the keys, time, errors and caller too when colored, by
+	// default only the level is
+	Theme *Theme
+
+	// ColorWholeLines colors the whole lines of warnings, errors and fatal
+	// entries with their level color when colored, so that they stand out
+	// when scrolling
+	ColorWholeLines bool
+
+	// FieldMap renames the fixed keys, such as the time and message
+	FieldMap FieldMap
+
+	// Values are quoted when they are empty or contain spaces or special
+	// characters, except the empty ones which are left bare when Strict as
+	// logfmt allows. ForceQuote quotes all of them, so that the output is
+	// unambiguous for parsers.
+	ForceQuote bool
+
+	// QuoteRune values are quoted with, a double quote by default. Other
+	// quotes can not be read back by the parse package.
+	QuoteRune rune
+
+	// KeyValueSeparator is written between keys and values, "=" by
+	// default, and PairSeparator between pairs, " " by default, for
+	// example ": " and " | " for key: value | key: value. The parse package
+	// only reads the defaults.
+	KeyValueSeparator string
+	PairSeparator     string
+
+	// LevelText customizes the level names, such as writing them in upper
+	// case
+	LevelText LevelText
+
+	// MaxMessageLength and MaxValueLength truncate the messages and param
+	// values longer than them, in bytes, noting their original length. Zero
+	// is for no maximum.
+	MaxMessageLength int
+	MaxValueLength   int
+
+	// Strict writes the entries as strict logfmt, which all logfmt parsers
+	// read, like the LogfmtFormatter. Keys are always followed by =, values
+	// are quoted whenever they contain spaces, equal signs, quotes or control
+	// characters, with those escaped, and invalid characters are removed
+	// from keys. The separators, QuoteRune and NewlineKeep are ignored.
+	Strict bool
+
+	// NewlineMode is how newlines in values are written. Values with
+	// newlines are quoted, which escapes them by default, NewlineReplace
+	// replaces them with spaces and NewlineKeep keeps them in the quotes.
+	NewlineMode NewlineMode
+
+	// CallerPrettyfier returns the function and file written for the caller,
+	// for example to shorten them. Empty values are not written.
+	CallerPrettyfier func(*runtime.Frame) (function, file string)
+
+	// whether the output is a terminal, checked on the first format
+	terminalOnce sync.Once
+	isTerminal   bool
 }
 
 func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	if f.isColored(entry) {
+		theme := f.Theme.orDefault(&textTheme)
+		if f.ColorWholeLines && entry.Level >= WarnLevel {
+			return f.formatColoredLine(entry, theme.level(entry.Level))
+		}
+		names := map[string]string{
+			f.FieldMap.resolve(levelKey): theme.level(entry.Level),
+			f.FieldMap.resolve(timeKey):  theme.Timestamp,
+			f.FieldMap.resolve(errKey):   theme.Error,
+			f.FieldMap.resolve(funcKey):  theme.Caller,
+			f.FieldMap.resolve(fileKey):  theme.Caller,
+		}
+		return f.format(entry, func(buffer *bytes.Buffer, key string, value interface{}) {
+			style, ok := names[key]
+			if _, isErr := value.(error); !ok && isErr {
+				style = theme.Error
+			}
+			if buffer.Len() > 0 {
+				buffer.WriteString(f.pairSeparator())
+			}
+			writeColored(buffer, true, theme.Key, f.key(key))
+			buffer.WriteString(f.keyValueSeparator())
+			if style == "" {
+				f.appendValue(buffer, value)
+				return
+			}
+			buffer.WriteString(style)
+			f.appendValue(buffer, value)
+			buffer.WriteString(colorReset)
+		})
+	}
+	return f.format(entry, f.appendData)
+}
+
+// formatColoredLine formats the entry without colors, and colors the line
+func (f *TextFormatter) formatColoredLine(entry *Entry, style string) ([]byte, error) {
+	formatted, err := f.format(entry, f.appendData)
+	if err != nil || style == "" {
+		return formatted, err
+	}
+	line := make([]byte, 0, len(formatted)+len(style)+len(colorReset))
+	line = append(line, style...)
+	line = append(line, formatted[:len(formatted)-1]...)
+	line = append(line, colorReset...)
+	return append(line, '\n'), nil
+}
+
+// textTheme only colors the levels, as the text formatter did before themes
+var textTheme = Theme{}
+
+// isColored checks whether the entry should be colored
+func (f *TextFormatter) isColored(entry *Entry) bool {
+	if f.DisableColors {
+		return false
+	}
+	if f.ForceColors {
+		return true
+	}
+	f.terminalOnce.Do(func() {
+		if entry.Logger != nil {
+			f.isTerminal = isTerminal(entry.output())
+		}
+	})
+	return f.isTerminal
+}
+
+// format writes the fields of the entry in order using the append function
+func (f *TextFormatter) format(entry *Entry, appendData func(*bytes.Buffer, string, interface{})) ([]byte, error) {
 	data := make(Params)
 	for k, v := range entry.Data {
 		data[k] = v
 	}
-	fixParamsClash(data, entry.HasCaller())
+	f.FieldMap.fixParamsClash(data, entry)
 	paramKeys := make([]string, 0, len(data))
 	for k := range data {
 		paramKeys = append(paramKeys, k)
 	}
 	var funcVal, fileVal string
-	fixedKeys := make([]string, 0, 4+len(data))
-	if !f.DisableTimestamp {
+	fixedKeys := make([]string, 0, 7)
+	if !f.DisableTimestamp && entry.Level >= f.HideTimestampBelow {
 		fixedKeys = append(fixedKeys, timeKey)
 	}
 	if entry.Message != "" {
 		fixedKeys = append(fixedKeys, msgKey)
 	}
+	if entry.EventName != "" {
+		fixedKeys = append(fixedKeys, eventKey)
+	}
 	fixedKeys = append(fixedKeys, levelKey)
 	if entry.err != "" {
 		fixedKeys = append(fixedKeys, errKey)
 	}
 	if entry.HasCaller() {
-		funcVal = entry.Caller.Function
+		funcVal, fileVal = callerFields(entry, f.CallerPrettyfier)
 		if funcVal != "" {
 			fixedKeys = append(fixedKeys, funcKey)
 		}
-		fileVal = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
 		if fileVal != "" {
 			fixedKeys = append(fixedKeys, fileKey)
 		}
 	}
-	if f.DisableSorting {
-		fixedKeys = append(fixedKeys, paramKeys...)
-	} else {
+	if f.SortingFunc != nil {
+		f.SortingFunc(paramKeys)
+	} else if !f.DisableSorting {
 		sort.Strings(paramKeys)
-		fixedKeys = append(fixedKeys, paramKeys...)
 	}
 	tsFormat := f.TimestampFormat
 	if tsFormat == "" {
@@ -63,30 +214,106 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	if buffer == nil {
 		buffer = &bytes.Buffer{}
 	}
-	for _, key := range fixedKeys {
+	for i, key := range fixedKeys {
 		var value interface{}
 		switch key {
 		case timeKey:
-			value = entry.Time.Format(tsFormat)
+			if f.EpochTimestamp != EpochNone {
+				value = f.EpochTimestamp.epoch(entry.Time)
+			} else {
+				value = entry.Time.Format(tsFormat)
+			}
 		case msgKey:
-			value = entry.Message
+			value = truncate(entry.Message, f.MaxMessageLength)
+		case eventKey:
+			value = entry.EventName
 		case levelKey:
-			value = entry.Level.String()
+			value = f.LevelText.name(entry.Level, entry.catalog())
 		case errKey:
-			value = entry.err
+			value = truncate(entry.err, f.MaxValueLength)
 		case funcKey:
 			value = funcVal
 		case fileKey:
 			value = fileVal
-		default:
-			value = data[key]
 		}
-		appendData(buffer, key, value)
+		appendData(buffer, f.FieldMap.resolve(key), value)
+		if key == levelKey && (i < len(fixedKeys)-1 || len(paramKeys) > 0) {
+			buffer.WriteString(f.LevelText.padding(value.(string), entry.catalog()))
+		}
+	}
+	for _, key := range paramKeys {
+		appendData(buffer, key, truncateText(data[key], f.MaxValueLength))
 	}
 	buffer.WriteByte('\n')
 	return buffer.Bytes(), nil
 }
 
+// appendData writes the pair with the quoting options of the formatter
+func (f *TextFormatter) appendData(buffer *bytes.Buffer, key string, value interface{}) {
+	if buffer.Len() > 0 {
+		buffer.WriteString(f.pairSeparator())
+	}
+	buffer.WriteString(f.key(key))
+	buffer.WriteString(f.keyValueSeparator())
+	f.appendValue(buffer, value)
+}
+
+// key returns the key as written, without invalid characters when strict
+func (f *TextFormatter) key(key string) string {
+	if f.Strict {
+		return logfmtKey(key)
+	}
+	return key
+}
+
+func (f *TextFormatter) keyValueSeparator() string {
+	if f.Strict || f.KeyValueSeparator == "" {
+		return "="
+	}
+	return f.KeyValueSeparator
+}
+
+func (f *TextFormatter) pairSeparator() string {
+	if f.Strict || f.PairSeparator == "" {
+		return " "
+	}
+	return f.PairSeparator
+}
+
+func (f *TextFormatter) appendValue(buffer *bytes.Buffer, value interface{}) {
+	stringVal, ok := value.(string)
+	if !ok {
+		stringVal = fmt.Sprint(value)
+	}
+	if f.NewlineMode == NewlineReplace {
+		stringVal = NewlineReplace.apply(stringVal)
+	}
+	if f.Strict {
+		if f.ForceQuote {
+			appendLogfmtQuoted(buffer, stringVal)
+		} else {
+			appendLogfmtValue(buffer, stringVal)
+		}
+		return
+	}
+	quote := f.ForceQuote || needsQuoting(stringVal)
+	if quote && f.NewlineMode == NewlineKeep && strings.Contains(stringVal, "\n") {
+		// the lines are quoted separately and joined by the newlines
+		lines := strings.Split(stringVal, "\n")
+		for i, line := range lines {
+			quoted := quoteWith(line, f.QuoteRune)
+			_, size := utf8.DecodeRuneInString(quoted)
+			lines[i] = quoted[size : len(quoted)-size]
+		}
+		q := quoteWith("", f.QuoteRune)
+		buffer.WriteString(q[:len(q)/2] + strings.Join(lines, "\n") + q[len(q)/2:])
+	} else if quote {
+		buffer.WriteString(quoteWith(stringVal, f.QuoteRune))
+	} else {
+		buffer.WriteString(stringVal)
+	}
+}
+
 func appendData(buffer *bytes.Buffer, key string, value interface{}) {
 	if buffer.Len() > 0 {
 		buffer.WriteByte(' ')
@@ -102,7 +329,7 @@ func appendValue(buffer *bytes.Buffer, value interface{}) {
 		stringVal = fmt.Sprint(value)
 	}
 	if needsQuoting(stringVal) {
-		buffer.WriteString(fmt.Sprintf("%q", stringVal))
+		buffer.WriteString(strconv.Quote(stringVal))
 	} else {
 		buffer.WriteString(stringVal)
 	}
@@ -122,3 +349,17 @@ func needsQuoting(text string) bool {
 	}
 	return false
 }
+
+// quoteWith quotes the text like strconv.Quote, with the quote rune in place
+// of double quotes
+func quoteWith(text string, quote rune) string {
+	quoted := strconv.Quote(text)
+	if quote == 0 || quote == '"' {
+		return quoted
+	}
+	// double quotes are always escaped by strconv.Quote
+	q := string(quote)
+	inner := strings.Replace(quoted[1:len(quoted)-1], `\"`, `"`, -1)
+	inner = strings.Replace(inner, q, `\`+q, -1)
+	return q + inner + q
+}
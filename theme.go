@@ -0,0 +1,67 @@
+package rogger
+
+// Theme is the ansi styles the colored formatters use for each element,
+// such as "\x1b[31m" for red. Empty styles leave the element uncolored, and
+// levels missing from Levels use the default level colors.
+type Theme struct {
+	// Levels styles the level of the entry
+	Levels map[Level]string
+
+	// Timestamp styles the time of the entry
+	Timestamp string
+
+	// Key styles the keys of the params
+	Key string
+
+	// Error styles the error values
+	Error string
+
+	// Caller styles the caller of the entry
+	Caller string
+}
+
+// themes for terminals with dark and light backgrounds
+var (
+	DarkTheme = Theme{
+		Levels: map[Level]string{
+			DebugLevel: colorGray,
+			InfoLevel:  colorCyan,
+			WarnLevel:  colorYellow,
+			ErrorLevel: colorRed,
+			FatalLevel: colorPurple,
+		},
+		Timestamp: colorDim,
+		Key:       colorCyan,
+		Error:     colorRed,
+		Caller:    colorDim,
+	}
+	LightTheme = Theme{
+		Levels: map[Level]string{
+			DebugLevel: "\x1b[90m",
+			InfoLevel:  "\x1b[34m",
+			WarnLevel:  "\x1b[1;33m",
+			ErrorLevel: "\x1b[1;31m",
+			FatalLevel: "\x1b[1;35m",
+		},
+		Timestamp: "\x1b[90m",
+		Key:       "\x1b[34m",
+		Error:     "\x1b[31m",
+		Caller:    "\x1b[90m",
+	}
+)
+
+// level returns the style of the level
+func (t *Theme) level(level Level) string {
+	if style, ok := t.Levels[level]; ok {
+		return style
+	}
+	return levelColor(level)
+}
+
+// orDefault returns the theme, or the default one when it is nil
+func (t *Theme) orDefault(theme *Theme) *Theme {
+	if t == nil {
+		return theme
+	}
+	return t
+}
@@ -0,0 +1,240 @@
+package rogger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// retry queue limits
+const (
+	maxPendingWrites = 1024
+	defaultHighWater = maxPendingWrites * 3 / 4
+	writeRetries     = 3
+	writeRetryDelay  = 100 * time.Millisecond
+)
+
+// Errors
+var (
+	ErrWriteTimeout = errors.New("write timed out, continuing in the background")
+	ErrQueueFull    = errors.New("background write queue is full, entry dropped")
+)
+
+// DropReason is why an entry was dropped without being written
+type DropReason int
+
+// Drop reasons
+const (
+	// DropQueueFull is for entries logged while the background write queue
+	// is full
+	DropQueueFull DropReason = iota
+
+	// DropWriteFailed is for entries the background writes failed to write
+	// after retrying
+	DropWriteFailed
+
+	// DropSampled is for entries dropped by the sampler
+	DropSampled
+)
+
+func (r DropReason) String() string {
+	switch r {
+	case DropQueueFull:
+		return "queue_full"
+	case DropWriteFailed:
+		return "write_failed"
+	case DropSampled:
+		return "sampled"
+	}
+	return "unknown"
+}
+
+// pendingWrite is a formatted entry waiting to be written in the background
+type pendingWrite struct {
+	out   io.Writer
+	level Level
+	p     []byte
+
+	// copy of the entry, kept for the drop callback only
+	entry *Entry
+}
+
+// writeResult is the outcome of a write
+type writeResult struct {
+	n   int
+	err error
+}
+
+// backgroundSettings are the settings of the logger used by its background
+// writes. They are read when the writes start, while the logger is locked,
+// as they may be changed in the meantime.
+type backgroundSettings struct {
+	errorHandler func(error)
+	onDrop       func(*Entry, DropReason)
+	catalog      Catalog
+	highWater    int
+}
+
+// backgroundSettings returns the current settings, it must be called with
+// the logger locked
+func (logger *Logger) backgroundSettings() backgroundSettings {
+	return backgroundSettings{
+		errorHandler: logger.ErrorHandler,
+		onDrop:       logger.OnDrop,
+		catalog:      logger.Catalog,
+		highWater:    logger.QueueHighWater,
+	}
+}
+
+// retryQueue takes over writing once a write times out, so that callers
+// are not blocked by a hung output. Entries logged while it is active are
+// queued behind the hung write to keep their order, and failed writes are
+// retried a few times.
+type retryQueue struct {
+	mu      sync.Mutex
+	active  bool
+	pending []pendingWrite
+	dropped uint64
+
+	// whether the queue reached the high water mark, until it is drained
+	aboveHighWater bool
+}
+
+// depth returns the number of entries waiting to be written
+func (q *retryQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// writeWithTimeout writes the entry, handing it over to the retry queue if
+// the output does not return before the logger write timeout
+func (logger *Logger) writeWithTimeout(out io.Writer, entry *Entry, p []byte) error {
+	w := pendingWrite{out: out, level: entry.Level, p: append([]byte(nil), p...)}
+	if logger.OnDrop != nil {
+		kept := *entry
+		kept.Buffer = nil
+		w.entry = &kept
+	}
+	q := &logger.retries
+	q.mu.Lock()
+	if q.active {
+		err := q.enqueue(w)
+		depth, reached := len(q.pending), q.reachedHighWater(logger.QueueHighWater)
+		q.mu.Unlock()
+		if reached && logger.OnQueueHighWater != nil {
+			logger.OnQueueHighWater(depth)
+		}
+		return err
+	}
+	q.mu.Unlock()
+
+	done := make(chan writeResult, 1)
+	go func() {
+		n, err := writeLevel(w.out, w.level, w.p)
+		done <- writeResult{n: n, err: err}
+	}()
+	timer := time.NewTimer(logger.WriteTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.err
+	case <-timer.C:
+		q.mu.Lock()
+		q.active = true
+		q.mu.Unlock()
+		go logger.drainRetries(w, done, logger.backgroundSettings())
+		return ErrWriteTimeout
+	}
+}
+
+// enqueue adds a write to the queue, it must be called with the lock held
+func (q *retryQueue) enqueue(w pendingWrite) error {
+	if len(q.pending) >= maxPendingWrites {
+		q.dropped++
+		return ErrQueueFull
+	}
+	q.pending = append(q.pending, w)
+	return nil
+}
+
+// reachedHighWater checks whether the queue just reached the mark, it must
+// be called with the lock held. It is reported once until the queue is
+// drained below the mark.
+func (q *retryQueue) reachedHighWater(mark int) bool {
+	if mark <= 0 {
+		mark = defaultHighWater
+	}
+	if len(q.pending) < mark {
+		q.aboveHighWater = false
+		return false
+	}
+	if q.aboveHighWater {
+		return false
+	}
+	q.aboveHighWater = true
+	return true
+}
+
+// drainRetries waits for the timed out write and then writes the queued
+// entries in order, until the queue is empty
+func (logger *Logger) drainRetries(inFlight pendingWrite, done chan writeResult, settings backgroundSettings) {
+	q := &logger.retries
+	if res := <-done; res.err != nil {
+		logger.retryWrite(inFlight.rest(res.n), res.err, settings)
+	}
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.active = false
+			q.aboveHighWater = false
+			q.mu.Unlock()
+			return
+		}
+		w := q.pending[0]
+		q.pending = q.pending[1:]
+		q.reachedHighWater(settings.highWater)
+		q.mu.Unlock()
+		if n, err := writeLevel(w.out, w.level, w.p); err != nil {
+			logger.retryWrite(w.rest(n), err, settings)
+		}
+	}
+}
+
+// retryWrite tries writing the rest of a failed entry again a few times
+func (logger *Logger) retryWrite(w pendingWrite, err error, settings backgroundSettings) {
+	for i := 0; i < writeRetries; i++ {
+		time.Sleep(writeRetryDelay)
+		var n int
+		if n, err = writeLevel(w.out, w.level, w.p); err == nil {
+			return
+		}
+		w = w.rest(n)
+	}
+	q := &logger.retries
+	q.mu.Lock()
+	q.dropped++
+	q.mu.Unlock()
+	logger.reportError(settings.errorHandler, ErrorWrite, fmt.Errorf(settings.catalog.translate(CatalogWriteFailed), err))
+	if w.entry != nil && settings.onDrop != nil {
+		settings.onDrop(w.entry, DropWriteFailed)
+	}
+}
+
+// rest returns the write without the bytes already written, so that a
+// partial write is not written twice
+func (w pendingWrite) rest(n int) pendingWrite {
+	if n > 0 && n <= len(w.p) {
+		w.p = w.p[n:]
+	}
+	return w
+}
+
+// droppedWrites returns the number of entries the retry queue dropped
+func (q *retryQueue) droppedWrites() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}